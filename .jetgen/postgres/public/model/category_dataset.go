@@ -8,17 +8,21 @@
 package model
 
 type CategoryDataset struct {
-	ID          int32 `sql:"primary_key"`
-	L1In        string
-	L2In        *string
-	L3In        *string
-	L4In        *string
-	L5In        *string
-	L6In        *string
-	L7In        *string
-	L8In        *string
-	FullPathOut string
-	NameOut     string
-	Version     string
-	Label       string
+	ID              int32 `sql:"primary_key"`
+	L1In            string
+	L2In            *string
+	L3In            *string
+	L4In            *string
+	L5In            *string
+	L6In            *string
+	L7In            *string
+	L8In            *string
+	FullPathOut     string
+	NameOut         string
+	Version         string
+	Label           string
+	CategoryIDOut   int32
+	MatchCategoryID *int32
+	LevelsIn        *string
+	InputText       string
 }
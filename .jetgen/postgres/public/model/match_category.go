@@ -18,4 +18,5 @@ type MatchCategory struct {
 	L7      *string
 	L8      *string
 	MatchID *int32
+	Levels  *string
 }
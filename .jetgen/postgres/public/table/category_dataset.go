@@ -17,19 +17,23 @@ type categoryDatasetTable struct {
 	postgres.Table
 
 	// Columns
-	ID          postgres.ColumnInteger
-	L1In        postgres.ColumnString
-	L2In        postgres.ColumnString
-	L3In        postgres.ColumnString
-	L4In        postgres.ColumnString
-	L5In        postgres.ColumnString
-	L6In        postgres.ColumnString
-	L7In        postgres.ColumnString
-	L8In        postgres.ColumnString
-	FullPathOut postgres.ColumnString
-	NameOut     postgres.ColumnString
-	Version     postgres.ColumnString
-	Label       postgres.ColumnString
+	ID              postgres.ColumnInteger
+	L1In            postgres.ColumnString
+	L2In            postgres.ColumnString
+	L3In            postgres.ColumnString
+	L4In            postgres.ColumnString
+	L5In            postgres.ColumnString
+	L6In            postgres.ColumnString
+	L7In            postgres.ColumnString
+	L8In            postgres.ColumnString
+	FullPathOut     postgres.ColumnString
+	NameOut         postgres.ColumnString
+	Version         postgres.ColumnString
+	Label           postgres.ColumnString
+	CategoryIDOut   postgres.ColumnInteger
+	MatchCategoryID postgres.ColumnInteger
+	LevelsIn        postgres.ColumnString
+	InputText       postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -70,40 +74,48 @@ func newCategoryDatasetTable(schemaName, tableName, alias string) *CategoryDatas
 
 func newCategoryDatasetTableImpl(schemaName, tableName, alias string) categoryDatasetTable {
 	var (
-		IDColumn          = postgres.IntegerColumn("id")
-		L1InColumn        = postgres.StringColumn("l1_in")
-		L2InColumn        = postgres.StringColumn("l2_in")
-		L3InColumn        = postgres.StringColumn("l3_in")
-		L4InColumn        = postgres.StringColumn("l4_in")
-		L5InColumn        = postgres.StringColumn("l5_in")
-		L6InColumn        = postgres.StringColumn("l6_in")
-		L7InColumn        = postgres.StringColumn("l7_in")
-		L8InColumn        = postgres.StringColumn("l8_in")
-		FullPathOutColumn = postgres.StringColumn("full_path_out")
-		NameOutColumn     = postgres.StringColumn("name_out")
-		VersionColumn     = postgres.StringColumn("version")
-		LabelColumn       = postgres.StringColumn("label")
-		allColumns        = postgres.ColumnList{IDColumn, L1InColumn, L2InColumn, L3InColumn, L4InColumn, L5InColumn, L6InColumn, L7InColumn, L8InColumn, FullPathOutColumn, NameOutColumn, VersionColumn, LabelColumn}
-		mutableColumns    = postgres.ColumnList{L1InColumn, L2InColumn, L3InColumn, L4InColumn, L5InColumn, L6InColumn, L7InColumn, L8InColumn, FullPathOutColumn, NameOutColumn, VersionColumn, LabelColumn}
+		IDColumn              = postgres.IntegerColumn("id")
+		L1InColumn            = postgres.StringColumn("l1_in")
+		L2InColumn            = postgres.StringColumn("l2_in")
+		L3InColumn            = postgres.StringColumn("l3_in")
+		L4InColumn            = postgres.StringColumn("l4_in")
+		L5InColumn            = postgres.StringColumn("l5_in")
+		L6InColumn            = postgres.StringColumn("l6_in")
+		L7InColumn            = postgres.StringColumn("l7_in")
+		L8InColumn            = postgres.StringColumn("l8_in")
+		FullPathOutColumn     = postgres.StringColumn("full_path_out")
+		NameOutColumn         = postgres.StringColumn("name_out")
+		VersionColumn         = postgres.StringColumn("version")
+		LabelColumn           = postgres.StringColumn("label")
+		CategoryIDOutColumn   = postgres.IntegerColumn("category_id_out")
+		MatchCategoryIDColumn = postgres.IntegerColumn("match_category_id")
+		LevelsInColumn        = postgres.StringColumn("levels_in")
+		InputTextColumn       = postgres.StringColumn("input_text")
+		allColumns            = postgres.ColumnList{IDColumn, L1InColumn, L2InColumn, L3InColumn, L4InColumn, L5InColumn, L6InColumn, L7InColumn, L8InColumn, FullPathOutColumn, NameOutColumn, VersionColumn, LabelColumn, CategoryIDOutColumn, MatchCategoryIDColumn, LevelsInColumn, InputTextColumn}
+		mutableColumns        = postgres.ColumnList{L1InColumn, L2InColumn, L3InColumn, L4InColumn, L5InColumn, L6InColumn, L7InColumn, L8InColumn, FullPathOutColumn, NameOutColumn, VersionColumn, LabelColumn, CategoryIDOutColumn, MatchCategoryIDColumn, LevelsInColumn, InputTextColumn}
 	)
 
 	return categoryDatasetTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:          IDColumn,
-		L1In:        L1InColumn,
-		L2In:        L2InColumn,
-		L3In:        L3InColumn,
-		L4In:        L4InColumn,
-		L5In:        L5InColumn,
-		L6In:        L6InColumn,
-		L7In:        L7InColumn,
-		L8In:        L8InColumn,
-		FullPathOut: FullPathOutColumn,
-		NameOut:     NameOutColumn,
-		Version:     VersionColumn,
-		Label:       LabelColumn,
+		ID:              IDColumn,
+		L1In:            L1InColumn,
+		L2In:            L2InColumn,
+		L3In:            L3InColumn,
+		L4In:            L4InColumn,
+		L5In:            L5InColumn,
+		L6In:            L6InColumn,
+		L7In:            L7InColumn,
+		L8In:            L8InColumn,
+		FullPathOut:     FullPathOutColumn,
+		NameOut:         NameOutColumn,
+		Version:         VersionColumn,
+		Label:           LabelColumn,
+		CategoryIDOut:   CategoryIDOutColumn,
+		MatchCategoryID: MatchCategoryIDColumn,
+		LevelsIn:        LevelsInColumn,
+		InputText:       InputTextColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
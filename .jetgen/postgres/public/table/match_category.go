@@ -27,6 +27,7 @@ type matchCategoryTable struct {
 	L7      postgres.ColumnString
 	L8      postgres.ColumnString
 	MatchID postgres.ColumnInteger
+	Levels  postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -77,8 +78,9 @@ func newMatchCategoryTableImpl(schemaName, tableName, alias string) matchCategor
 		L7Column       = postgres.StringColumn("l7")
 		L8Column       = postgres.StringColumn("l8")
 		MatchIDColumn  = postgres.IntegerColumn("match_id")
-		allColumns     = postgres.ColumnList{IDColumn, L1Column, L2Column, L3Column, L4Column, L5Column, L6Column, L7Column, L8Column, MatchIDColumn}
-		mutableColumns = postgres.ColumnList{L1Column, L2Column, L3Column, L4Column, L5Column, L6Column, L7Column, L8Column, MatchIDColumn}
+		LevelsColumn   = postgres.StringColumn("levels")
+		allColumns     = postgres.ColumnList{IDColumn, L1Column, L2Column, L3Column, L4Column, L5Column, L6Column, L7Column, L8Column, MatchIDColumn, LevelsColumn}
+		mutableColumns = postgres.ColumnList{L1Column, L2Column, L3Column, L4Column, L5Column, L6Column, L7Column, L8Column, MatchIDColumn, LevelsColumn}
 	)
 
 	return matchCategoryTable{
@@ -95,6 +97,7 @@ func newMatchCategoryTableImpl(schemaName, tableName, alias string) matchCategor
 		L7:      L7Column,
 		L8:      L8Column,
 		MatchID: MatchIDColumn,
+		Levels:  LevelsColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -10,9 +10,18 @@ package table
 // UseSchema sets a new schema name for all generated table SQL builder types. It is recommended to invoke
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
+	AuditLog = AuditLog.FromSchema(schema)
 	Category = Category.FromSchema(schema)
 	CategoryDataset = CategoryDataset.FromSchema(schema)
+	CategoryRemap = CategoryRemap.FromSchema(schema)
+	CategorySnapshot = CategorySnapshot.FromSchema(schema)
+	DatasetAlias = DatasetAlias.FromSchema(schema)
+	DatasetPartition = DatasetPartition.FromSchema(schema)
+	DatasetVersion = DatasetVersion.FromSchema(schema)
+	GenerationCheckpoint = GenerationCheckpoint.FromSchema(schema)
+	JobLedger = JobLedger.FromSchema(schema)
 	MatchCategory = MatchCategory.FromSchema(schema)
+	PipelineDefinition = PipelineDefinition.FromSchema(schema)
 	SchemaMigrations = SchemaMigrations.FromSchema(schema)
 	Users = Users.FromSchema(schema)
 }
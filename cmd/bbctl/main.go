@@ -0,0 +1,172 @@
+// Package main provides bbctl, a command line tool for managing generated datasets.
+// It connects to the same database as the Lambda function (see store.NewDB) and exposes
+// administrative commands that do not need to go through the SQS queue.
+// Promotions (alias set) are recorded in the audit log under the name of the OS user.
+//
+// Usage:
+//
+//	bbctl alias list
+//	bbctl alias get <name>
+//	bbctl alias set <version> <name>...
+//	bbctl alias delete <name>...
+//	bbctl audit list [<version>]
+//	bbctl snapshot export <version>
+//	bbctl snapshot diff <version>
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/user"
+
+	"github.com/joho/godotenv"
+	"github.com/opplieam/bb-transform/internal/audit"
+	"github.com/opplieam/bb-transform/internal/store"
+	"github.com/opplieam/bb-transform/internal/transform"
+
+	_ "github.com/lib/pq"
+)
+
+var (
+	ErrUsage = errors.New(
+		"usage: bbctl alias list | get <name> | set <version> <name>... | delete <name>... | bbctl audit list [<version>] | " +
+			"bbctl snapshot export <version> | diff <version>",
+	)
+)
+
+// actor returns the name of the OS user running bbctl, recorded in the audit log.
+func actor() string {
+	u, err := user.Current()
+	if err != nil {
+		return os.Getenv("USER")
+	}
+	return u.Username
+}
+
+// promote points the aliases at the version and records the promotion in the audit log.
+func promote(aliases *transform.Aliases, al audit.Storer, version string, names []string) error {
+	entry := audit.Start(audit.OpPromote, audit.SourceCLI)
+	entry.Version, entry.Actor = version, actor()
+	entry.SetConfig(map[string]any{"names": names})
+	err := aliases.Set(version, names...)
+	entry.Finish(err)
+	if rErr := al.Record(entry); rErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to record audit entry: %w", rErr))
+	}
+	return err
+}
+
+func runAudit(al audit.Storer, args []string) error {
+	if len(args) == 0 || args[0] != "list" || len(args) > 2 {
+		return ErrUsage
+	}
+	q := audit.Query{}
+	if len(args) == 2 {
+		q.Version = args[1]
+	}
+	entries, err := al.Entries(q)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%dms\t%s\n",
+			e.StartedAt.Format("2006-01-02 15:04:05"), e.Operation, e.Version, e.Actor, e.Source, e.Outcome, e.DurationMS, e.Error,
+		)
+	}
+	return nil
+}
+
+func runAlias(aliases *transform.Aliases, al audit.Storer, args []string) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+	switch cmd, args := args[0], args[1:]; {
+	case cmd == "list" && len(args) == 0:
+		list, err := aliases.List()
+		if err != nil {
+			return err
+		}
+		for _, v := range list {
+			fmt.Printf("%s\t%s\t%s\n", v.Name, v.Version, v.UpdatedAt.Format("2006-01-02 15:04:05"))
+		}
+		return nil
+	case cmd == "get" && len(args) == 1:
+		version, err := aliases.Resolve(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(version)
+		return nil
+	case cmd == "set" && len(args) >= 2:
+		return promote(aliases, al, args[0], args[1:])
+	case cmd == "delete" && len(args) >= 1:
+		for _, name := range args {
+			if err := aliases.Delete(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return ErrUsage
+	}
+}
+
+// runSnapshot prints the taxonomy snapshot of a version (export) or its differences with the current
+// category tree (diff) as JSON.
+func runSnapshot(t *transform.Transform, args []string) error {
+	if len(args) != 2 {
+		return ErrUsage
+	}
+	var v any
+	var err error
+	switch args[0] {
+	case "export":
+		v, err = t.Snapshot(args[1])
+	case "diff":
+		v, err = t.CompareSnapshot(args[1])
+	default:
+		return ErrUsage
+	}
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func run(args []string) error {
+	if len(args) == 0 || (args[0] != "alias" && args[0] != "audit" && args[0] != "snapshot") {
+		return ErrUsage
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	if err := godotenv.Load(); err != nil {
+		logger.Info("no .env file")
+	}
+	db, err := store.NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	al := store.NewAuditStore(db)
+	if args[0] == "audit" {
+		return runAudit(al, args[1:])
+	}
+	cs := store.NewCategoryStore(db)
+	if args[0] == "snapshot" {
+		return runSnapshot(transform.NewTransform(logger, cs, transform.Config{}), args[1:])
+	}
+	return runAlias(transform.NewAliases(logger, cs), al, args[1:])
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -4,16 +4,30 @@
 // It utilizes the `store` package for database interactions, specifically for creating and managing category data.
 // Logging is performed using the `slog` package, providing structured logs for monitoring and debugging.
 // This setup is intended for deployment in an AWS environment where the Lambda function is invoked by SQS messages.
+// For local development, the same handler can consume messages from an SQS-compatible queue or a directory
+// of JSON files instead (see workerSource), and an HTTP API can be served (see serveHTTP).
 package main
 
 import (
+	"context"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/joho/godotenv"
+	"github.com/opplieam/bb-transform/internal/api"
+	"github.com/opplieam/bb-transform/internal/audit"
+	"github.com/opplieam/bb-transform/internal/auth"
 	"github.com/opplieam/bb-transform/internal/lambdahandler"
+	"github.com/opplieam/bb-transform/internal/notify"
+	"github.com/opplieam/bb-transform/internal/queue"
 	"github.com/opplieam/bb-transform/internal/store"
 	"github.com/opplieam/bb-transform/internal/transform"
 
@@ -62,17 +76,121 @@ func run() error {
 			TestRatio:     testRatio,
 		}
 		t := transform.NewTransform(logger, cs, tCfg)
-		if err = t.GenerateDataset(); err != nil {
-			logger.Error("failed to generate dataset", "error", err)
-			return err
-		}
-	} else {
-		lh := lambdahandler.NewHandler(logger, cs)
+		_, err = t.GenerateDataset(context.Background())
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if addr := os.Getenv(httpAddrEnv); addr != "" {
+		return serveHTTP(ctx, logger, cs, auth.NewAuthenticator(logger, store.NewUserStore(db)), store.NewAuditStore(db), addr)
+	}
+	notifier, err := notify.FromEnv(ctx)
+	if err != nil {
+		return err
+	}
+	sender, err := queue.FromEnv(ctx)
+	if err != nil {
+		return err
+	}
+	src, err := workerSource(ctx)
+	if err != nil {
+		return err
+	}
+	if src != nil && sender == nil {
+		// Continuations and partition jobs go back to the queue the worker consumes from.
+		sender = src.sender
+	}
+
+	ps := store.NewPipelineStore(db)
+	lh := lambdahandler.NewHandler(logger, cs, ps, store.NewJobStore(db), notifier, sender, store.NewAuditStore(db))
+	if src == nil {
 		lambda.Start(lh.HandleSQSEvent)
+		return nil
+	}
+	w := queue.NewWorker(logger, src.Source, lh.HandleSQSEvent)
+	w.Timeout = workerTimeout
+	w.RetryDelay = workerRetryDelay
+	return w.Run(ctx)
+}
+
+// Environment variables selecting the local worker mode, which runs the handler outside of AWS Lambda.
+// WORKER_QUEUE_URL consumes from an SQS-compatible queue (set AWS_ENDPOINT_URL for ElasticMQ or LocalStack),
+// WORKER_DIR consumes the *.json files of a directory.
+const (
+	workerQueueURLEnv = "WORKER_QUEUE_URL"
+	workerDirEnv      = "WORKER_DIR"
+)
+
+const (
+	// workerTimeout is the deadline of every message, the same as the Lambda timeout in terraform/lambda.tf.
+	workerTimeout    = 20 * time.Second
+	workerRetryDelay = 30 * time.Second
+)
+
+// httpAddrEnv is the environment variable holding the address the HTTP API listens on, e.g. ":8080".
+// When it is set, the HTTP API is served instead of the Lambda function.
+const httpAddrEnv = "HTTP_ADDR"
+
+// serveHTTP serves the HTTP API until ctx is canceled, then waits for running generations to finish.
+// Admin requests are authenticated by the Authenticator a, which checks credentials against the 'users' table,
+// and are recorded in the audit log al.
+func serveHTTP(
+	ctx context.Context, logger *slog.Logger, cs *store.CategoryStore, a *auth.Authenticator, al audit.Storer, addr string,
+) error {
+	s := api.NewServer(context.WithoutCancel(ctx), logger, cs, a, al)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
 	}
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info("serving http api", "addr", addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	logger.Info("waiting for running generations")
+	s.Wait()
 	return nil
 }
 
+// source is a worker queue together with the Sender writing to it.
+type source struct {
+	queue.Source
+	sender queue.Sender
+}
+
+// workerSource returns the queue selected by workerQueueURLEnv or workerDirEnv, or nil to run as a Lambda function.
+func workerSource(ctx context.Context) (*source, error) {
+	if queueURL := os.Getenv(workerQueueURLEnv); queueURL != "" {
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		client := sqs.NewFromConfig(cfg)
+		return &source{Source: queue.NewSQSSource(client, queueURL), sender: queue.NewSQS(client, queueURL)}, nil
+	}
+	if dir := os.Getenv(workerDirEnv); dir != "" {
+		src, err := queue.NewDirSource(dir)
+		if err != nil {
+			return nil, err
+		}
+		return &source{Source: src, sender: queue.NewDir(dir)}, nil
+	}
+	return nil, nil //nolint:nilnil // Not in worker mode
+}
+
 func main() {
 	if err := run(); err != nil {
 		log.Fatal(err)
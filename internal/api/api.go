@@ -0,0 +1,339 @@
+// Package api provides an HTTP API for triggering and monitoring dataset generation, so that teams without
+// AWS credentials can request datasets. It runs the same transform.Transform as the Lambda function
+// against the same store, with the generation running in the background of the server process.
+package api
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/opplieam/bb-transform/internal/audit"
+	"github.com/opplieam/bb-transform/internal/auth"
+	"github.com/opplieam/bb-transform/internal/transform"
+)
+
+// Run statuses of a generation started through the API.
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+// maxBodyBytes is the largest request body accepted by POST /datasets.
+const maxBodyBytes = 1 << 20
+
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrRunInProgress  = errors.New("a generation of this version is already running")
+)
+
+// Storer is the storage used by the API: the dataset generation and the lifecycle of dataset versions.
+type Storer interface {
+	transform.CategoryStorer
+	transform.VersionStorer
+}
+
+// Run is a generation started through the API. Runs are kept in memory by the Server that started them.
+type Run struct {
+	Version    string         `json:"version"`
+	Actor      string         `json:"actor,omitempty"`
+	Status     string         `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	Counts     map[string]int `json:"counts,omitempty"`
+	Warnings   []string       `json:"warnings,omitempty"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+}
+
+// DatasetStatus is the response of GET /datasets/{version}: the latest Run of the version started by this server,
+// if any, and the bookkeeping and row counts of the version once it has been generated.
+type DatasetStatus struct {
+	Version string                    `json:"version"`
+	Run     *Run                      `json:"run,omitempty"`
+	Stats   *transform.VersionSummary `json:"stats,omitempty"`
+}
+
+// Server serves the dataset API:
+//   - POST /datasets starts generating the dataset described by the transform.Config in the body.
+//   - GET /datasets/{version} returns the DatasetStatus of a version.
+//   - GET /datasets/{version}/export streams the rows of a version, optionally of one split (?label=)
+//     as JSON lines or CSV (?format=).
+//   - DELETE /datasets/{version} deletes a version that is not being generated, and is neither immutable
+//     nor aliased unless ?force=true.
+//   - GET /audit returns the audit log, optionally filtered by ?version=, ?operation=, ?actor= and ?since=
+//     (RFC 3339), at most ?limit= entries.
+//
+// POST, DELETE and GET /audit require HTTP basic authentication of an active user (see auth.Authenticator.Middleware),
+// who is recorded as the actor of the generated version and of the audit entries of generations and deletions.
+type Server struct {
+	log       *slog.Logger
+	store     Storer
+	auth      *auth.Authenticator
+	audit     audit.Storer
+	lifecycle *transform.Lifecycle
+	ctx       context.Context
+
+	mu   sync.Mutex
+	runs map[string]*Run
+	wg   sync.WaitGroup
+}
+
+// NewServer creates a new Server that authenticates admin requests with a and records them in al.
+// Generations started through the API run with ctx.
+func NewServer(ctx context.Context, l *slog.Logger, s Storer, a *auth.Authenticator, al audit.Storer) *Server {
+	return &Server{
+		log:       l.With("component", "api"),
+		store:     s,
+		auth:      a,
+		audit:     al,
+		lifecycle: transform.NewLifecycle(l, s, ""),
+		ctx:       ctx,
+		runs:      make(map[string]*Run),
+	}
+}
+
+// Handler returns the HTTP handler of the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST /datasets", s.auth.Middleware(http.HandlerFunc(s.createDataset)))
+	mux.HandleFunc("GET /datasets/{version}", s.getDataset)
+	mux.HandleFunc("GET /datasets/{version}/export", s.exportDataset)
+	mux.Handle("DELETE /datasets/{version}", s.auth.Middleware(http.HandlerFunc(s.deleteDataset)))
+	mux.Handle("GET /audit", s.auth.Middleware(http.HandlerFunc(s.listAudit)))
+	return mux
+}
+
+// Wait blocks until every generation started through the API has finished.
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+// createDataset validates the config, resolves its version and starts the generation in the background.
+// The actor of the config is always the authenticated user. Bodies are limited to maxBodyBytes.
+// It responds with 202 Accepted and the Run, before the generation has finished.
+func (s *Server) createDataset(w http.ResponseWriter, r *http.Request) {
+	var cfg transform.Config
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&cfg); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %w", ErrInvalidRequest, err))
+		return
+	}
+	// Partitions and continuations are only started by the Lambda function.
+	if cfg.Partition != nil || cfg.Resume {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: partition and resume cannot be set", ErrInvalidRequest))
+		return
+	}
+	user, _ := auth.UserFrom(r.Context())
+	cfg.Actor = user.Username
+	if err := cfg.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	t := transform.NewTransform(s.log, s.store, cfg)
+	if err := t.ResolveVersion(); err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+
+	run, err := s.startRun(t, cfg)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	w.Header().Set("Location", "/datasets/"+run.Version)
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+// startRun records a running Run for the version of t and generates its dataset in the background.
+// The generation is recorded in the audit log with cfg once it has finished.
+func (s *Server) startRun(t *transform.Transform, cfg transform.Config) (Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.runs[t.Version()]; ok && prev.Status == RunStatusRunning {
+		return Run{}, fmt.Errorf("%w: %s", ErrRunInProgress, t.Version())
+	}
+	run := &Run{Version: t.Version(), Actor: cfg.Actor, Status: RunStatusRunning, StartedAt: time.Now()}
+	s.runs[run.Version] = run
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		entry := audit.Start(audit.OpGenerate, audit.SourceAPI)
+		entry.Version, entry.Actor = run.Version, cfg.Actor
+		entry.SetConfig(cfg)
+		result, err := t.GenerateDataset(s.ctx)
+		entry.Finish(err)
+		s.recordAudit(entry)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		finished := time.Now()
+		run.FinishedAt = &finished
+		run.Warnings = result.Warnings
+		if err != nil {
+			run.Status, run.Error = RunStatusFailed, err.Error()
+			return
+		}
+		run.Status, run.Counts = RunStatusSucceeded, result.Counts
+	}()
+	return *run, nil
+}
+
+// run returns a copy of the latest Run of the version, or nil.
+func (s *Server) run(version string) *Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[version]
+	if !ok {
+		return nil
+	}
+	c := *run
+	return &c
+}
+
+func (s *Server) getDataset(w http.ResponseWriter, r *http.Request) {
+	version := r.PathValue("version")
+	status := DatasetStatus{Version: version, Run: s.run(version)}
+	stats, err := s.lifecycle.Stats(version)
+	switch {
+	case err == nil:
+		status.Stats = &stats
+	case errors.Is(err, transform.ErrVersionNotFound) && status.Run != nil:
+	default:
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) exportDataset(w http.ResponseWriter, r *http.Request) {
+	version := r.PathValue("version")
+	format := cmp.Or(r.URL.Query().Get("format"), transform.ExportFormatJSONL)
+	label := r.URL.Query().Get("label")
+
+	contentType := "application/x-ndjson"
+	switch format {
+	case transform.ExportFormatJSONL:
+	case transform.ExportFormatCSV:
+		contentType = "text/csv"
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %s", transform.ErrInvalidExportFormat, format))
+		return
+	}
+	if _, err := s.store.VersionInfo(version); err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	rows, err := s.lifecycle.WriteExport(r.Context(), w, version, format, label)
+	if err != nil {
+		// The status line may already have been sent with the first rows.
+		s.log.Error("failed to export dataset", "version", version, "error", err)
+		return
+	}
+	s.log.Info("exported dataset", "version", version, "label", label, "format", format, "rows", rows)
+}
+
+// deleteDataset deletes a version through transform.Lifecycle.Delete, which rejects immutable and aliased versions
+// unless ?force=true is set.
+func (s *Server) deleteDataset(w http.ResponseWriter, r *http.Request) {
+	version := r.PathValue("version")
+	force := r.URL.Query().Get("force") == "true"
+
+	if run := s.run(version); run != nil && run.Status == RunStatusRunning {
+		writeError(w, http.StatusConflict, fmt.Errorf("%w: %s", ErrRunInProgress, version))
+		return
+	}
+	// A generation started in the meantime holds the version lock, so Delete fails with ErrVersionLocked.
+	user, _ := auth.UserFrom(r.Context())
+	entry := audit.Start(audit.OpDelete, audit.SourceAPI)
+	entry.Version, entry.Actor = version, user.Username
+	err := s.lifecycle.Delete(version, force)
+	entry.Finish(err)
+	s.recordAudit(entry)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	s.log.Info("deleted dataset", "version", version, "actor", user.Username)
+	s.mu.Lock()
+	if run, ok := s.runs[version]; ok && run.Status != RunStatusRunning {
+		delete(s.runs, version)
+	}
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listAudit(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q := audit.Query{Version: params.Get("version"), Operation: params.Get("operation"), Actor: params.Get("actor")}
+	if since := params.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: since: %w", ErrInvalidRequest, err))
+			return
+		}
+		q.Since = t
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: limit: %s", ErrInvalidRequest, limit))
+			return
+		}
+		q.Limit = n
+	}
+	entries, err := s.audit.Entries(q)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// recordAudit appends the entry to the audit log. A failure to record is logged and does not fail the request.
+func (s *Server) recordAudit(e audit.Entry) {
+	if err := s.audit.Record(e); err != nil {
+		s.log.Error("failed to record audit entry", "operation", e.Operation, "version", e.Version, "error", err)
+	}
+}
+
+// statusOf maps an error to the HTTP status code of the response.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, transform.ErrVersionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, transform.ErrVersionImmutable), errors.Is(err, transform.ErrVersionAliased),
+		errors.Is(err, transform.ErrVersionLocked), errors.Is(err, transform.ErrVersionNotReserved),
+		errors.Is(err, transform.ErrVersionBuilding),
+		errors.Is(err, ErrRunInProgress):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidRequest), errors.Is(err, transform.ErrInvalidVersionTemplate),
+		errors.Is(err, transform.ErrInvalidExportFormat):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
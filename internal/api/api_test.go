@@ -0,0 +1,234 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/opplieam/bb-transform/internal/audit"
+	"github.com/opplieam/bb-transform/internal/auth"
+	"github.com/opplieam/bb-transform/internal/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+// userStore is an auth.UserStorer holding the active user "alice" with the password "secret".
+type userStore struct{}
+
+func (userStore) UserByUsername(username string) (model.Users, error) {
+	if username != "alice" {
+		return model.Users{}, auth.ErrUserNotFound
+	}
+	h, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		return model.Users{}, err
+	}
+	return model.Users{ID: 1, Username: "alice", Password: string(h), Active: true}, nil
+}
+
+func newServer(s Storer) *Server {
+	return newServerWithAudit(s, &audit.Memory{})
+}
+
+func newServerWithAudit(s Storer, al audit.Storer) *Server {
+	return NewServer(context.Background(), testLogger(), s, auth.NewAuthenticator(testLogger(), userStore{}), al)
+}
+
+// serve sends the request authenticated as "alice".
+func serve(t *testing.T, s *Server, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.SetBasicAuth("alice", "secret")
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateDataset(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		mockBehavior func(storer *MockStorer)
+		wantStatus   int
+		wantRun      string
+	}{
+		{
+			name:         "Invalid body",
+			body:         `{"version":`,
+			mockBehavior: func(storer *MockStorer) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid config",
+			body:         `{"version":"v1","path_shape":"xml"}`,
+			mockBehavior: func(storer *MockStorer) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "Partition set",
+			body:         `{"version":"v1","train_ratio":100,"partition":{"index":0}}`,
+			mockBehavior: func(storer *MockStorer) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "Resume set",
+			body:         `{"version":"v1","train_ratio":100,"resume":true}`,
+			mockBehavior: func(storer *MockStorer) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "Body too large",
+			body:         `{"version":"v1","text_separator":"` + strings.Repeat(" ", maxBodyBytes) + `"}`,
+			mockBehavior: func(storer *MockStorer) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "Accepted, generation fails",
+			body: `{"version":"v1","train_ratio":100}`,
+			mockBehavior: func(storer *MockStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(nil, transform.ErrVersionLocked)
+				storer.EXPECT().ListVersions().Return(nil, nil)
+			},
+			wantStatus: http.StatusAccepted,
+			wantRun:    RunStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorer := NewMockStorer(t)
+			tt.mockBehavior(mockStorer)
+			s := newServer(mockStorer)
+
+			rec := serve(t, s, http.MethodPost, "/datasets", tt.body)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantRun == "" {
+				return
+			}
+			assert.Equal(t, "/datasets/v1", rec.Header().Get("Location"))
+			s.Wait()
+
+			rec = serve(t, s, http.MethodGet, "/datasets/v1", "")
+			require.Equal(t, http.StatusOK, rec.Code)
+			var status DatasetStatus
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+			require.NotNil(t, status.Run)
+			assert.Equal(t, tt.wantRun, status.Run.Status)
+			assert.Equal(t, "alice", status.Run.Actor)
+			assert.Nil(t, status.Stats)
+		})
+	}
+}
+
+func TestGetDataset(t *testing.T) {
+	mockStorer := NewMockStorer(t)
+	mockStorer.EXPECT().ListVersions().Return([]transform.VersionSummary{
+		{VersionInfo: transform.VersionInfo{Version: "v1", Status: transform.VersionStatusActive}, Rows: 3},
+	}, nil)
+	s := newServer(mockStorer)
+
+	rec := serve(t, s, http.MethodGet, "/datasets/v1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status DatasetStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	require.NotNil(t, status.Stats)
+	assert.Equal(t, int64(3), status.Stats.Rows)
+
+	rec = serve(t, s, http.MethodGet, "/datasets/v2", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestExportDataset(t *testing.T) {
+	mockStorer := NewMockStorer(t)
+	mockStorer.EXPECT().VersionInfo("v1").Return(transform.VersionInfo{Version: "v1"}, nil)
+	mockStorer.EXPECT().VersionInfo("v2").Return(transform.VersionInfo{}, transform.ErrVersionNotFound)
+	mockStorer.EXPECT().EachDatasetRow(mock.Anything, "v1", transform.LabelTest, mock.Anything).RunAndReturn(
+		func(_ context.Context, _, _ string, fn func(model.CategoryDataset) error) error {
+			return fn(model.CategoryDataset{InputText: "b", Version: "v1", Label: transform.LabelTest})
+		},
+	)
+	s := newServer(mockStorer)
+
+	rec := serve(t, s, http.MethodGet, "/datasets/v1/export?label=test&format=csv", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], ",b,")
+
+	assert.Equal(t, http.StatusBadRequest, serve(t, s, http.MethodGet, "/datasets/v1/export?format=xml", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, s, http.MethodGet, "/datasets/v2/export", "").Code)
+}
+
+func TestDeleteDataset(t *testing.T) {
+	mockStorer := NewMockStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, mock.Anything, false, time.Duration(0)).Return(func() error { return nil }, nil)
+	mockStorer.EXPECT().VersionInfo("v1").Return(transform.VersionInfo{Version: "v1"}, nil)
+	mockStorer.EXPECT().VersionInfo("v2").Return(transform.VersionInfo{Version: "v2", Immutable: true}, nil)
+	mockStorer.EXPECT().VersionInfo("v3").Return(transform.VersionInfo{}, transform.ErrVersionNotFound)
+	mockStorer.EXPECT().ListAliases().Return(nil, nil)
+	mockStorer.EXPECT().DeleteVersion("v1").Return(nil)
+	mockStorer.EXPECT().DeleteVersion("v2").Return(nil)
+	al := &audit.Memory{}
+	s := newServerWithAudit(mockStorer, al)
+
+	assert.Equal(t, http.StatusNoContent, serve(t, s, http.MethodDelete, "/datasets/v1", "").Code)
+	assert.Equal(t, http.StatusConflict, serve(t, s, http.MethodDelete, "/datasets/v2", "").Code)
+	assert.Equal(t, http.StatusNoContent, serve(t, s, http.MethodDelete, "/datasets/v2?force=true", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, s, http.MethodDelete, "/datasets/v3", "").Code)
+
+	entries, err := al.Entries(audit.Query{})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, audit.Entry{
+		ID: 1, Operation: audit.OpDelete, Version: "v1", Actor: "alice", Source: audit.SourceAPI, Outcome: audit.OutcomeSucceeded,
+		StartedAt: entries[3].StartedAt, FinishedAt: entries[3].FinishedAt, DurationMS: entries[3].DurationMS,
+	}, entries[3])
+	assert.Equal(t, audit.OutcomeFailed, entries[2].Outcome)
+	assert.Equal(t, audit.OutcomeSucceeded, entries[1].Outcome)
+	assert.Equal(t, audit.OutcomeFailed, entries[0].Outcome)
+}
+
+func TestListAudit(t *testing.T) {
+	al := &audit.Memory{}
+	require.NoError(t, al.Record(audit.Entry{Operation: audit.OpGenerate, Version: "v1", Actor: "alice"}))
+	require.NoError(t, al.Record(audit.Entry{Operation: audit.OpDelete, Version: "v2", Actor: "alice"}))
+	s := newServerWithAudit(NewMockStorer(t), al)
+
+	rec := serve(t, s, http.MethodGet, "/audit?version=v2", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var entries []audit.Entry
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, audit.OpDelete, entries[0].Operation)
+
+	assert.Equal(t, http.StatusBadRequest, serve(t, s, http.MethodGet, "/audit?limit=x", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(t, s, http.MethodGet, "/audit?since=yesterday", "").Code)
+}
+
+func TestAuthentication(t *testing.T) {
+	s := newServer(NewMockStorer(t))
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/datasets", strings.NewReader(`{"version":"v1"}`)))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/datasets/v1", nil)
+	req.SetBasicAuth("alice", "wrong")
+	s.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,139 @@
+// Package audit records who generated, cleaned up, deleted or promoted dataset versions, when, with which config
+// and with which outcome. Entries are appended to the 'audit_log' table by the Lambda handler, the HTTP API and bbctl,
+// and are never updated or deleted, so they outlive the CloudWatch logs of the operations.
+package audit
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Operations recorded in the audit log.
+const (
+	OpGenerate = "generate"
+	OpCleanup  = "cleanup"
+	OpDelete   = "delete"
+	OpPromote  = "promote"
+)
+
+// Outcomes of an operation. OutcomeContinued marks a generation that ran out of time and was re-enqueued;
+// its continuation is recorded as another entry.
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+	OutcomeContinued = "continued"
+)
+
+// Sources of an operation: the surface it was requested through.
+const (
+	SourceLambda = "lambda"
+	SourceAPI    = "api"
+	SourceCLI    = "cli"
+)
+
+// DefaultLimit is the number of entries returned by a Query without a Limit.
+const DefaultLimit = 100
+
+// Entry is a single operation in the audit log.
+// Actor is the user who requested the operation, if known, and MessageID the SQS message it was requested with.
+// Config is the request of the operation as JSON, e.g. the transform.Config of a generation.
+type Entry struct {
+	ID         int64           `json:"id"`
+	Operation  string          `json:"operation"`
+	Version    string          `json:"version,omitempty"`
+	Actor      string          `json:"actor,omitempty"`
+	MessageID  string          `json:"message_id,omitempty"`
+	Source     string          `json:"source"`
+	Config     json.RawMessage `json:"config,omitempty"`
+	Outcome    string          `json:"outcome"`
+	Error      string          `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	DurationMS int64           `json:"duration_ms"`
+}
+
+// Start returns an Entry for an operation requested through source, started now.
+func Start(operation, source string) Entry {
+	return Entry{Operation: operation, Source: source, StartedAt: time.Now()}
+}
+
+// Finish sets the outcome, error and timing of the entry from the result of the operation.
+func (e *Entry) Finish(err error) {
+	e.Outcome, e.Error = OutcomeSucceeded, ""
+	if err != nil {
+		e.Outcome, e.Error = OutcomeFailed, err.Error()
+	}
+	e.FinishedAt = time.Now()
+	e.DurationMS = e.FinishedAt.Sub(e.StartedAt).Milliseconds()
+}
+
+// SetConfig stores v as the JSON config of the entry. Values that cannot be marshaled are left out.
+func (e *Entry) SetConfig(v any) {
+	if b, err := json.Marshal(v); err == nil {
+		e.Config = b
+	}
+}
+
+// Query selects audit entries. Empty fields match every entry.
+// Entries are returned newest first, at most Limit (DefaultLimit when zero) of them.
+type Query struct {
+	Version   string    `json:"version"`
+	Operation string    `json:"operation"`
+	Actor     string    `json:"actor"`
+	Since     time.Time `json:"since"`
+	Limit     int       `json:"limit"`
+}
+
+func (q Query) limit() int {
+	if q.Limit <= 0 {
+		return DefaultLimit
+	}
+	return q.Limit
+}
+
+// Match reports whether the entry is selected by the query.
+func (q Query) Match(e Entry) bool {
+	return (q.Version == "" || e.Version == q.Version) &&
+		(q.Operation == "" || e.Operation == q.Operation) &&
+		(q.Actor == "" || e.Actor == q.Actor) &&
+		(q.Since.IsZero() || !e.StartedAt.Before(q.Since))
+}
+
+// Storer appends entries to the audit log and queries them.
+type Storer interface {
+	Record(e Entry) error
+	Entries(q Query) ([]Entry, error)
+}
+
+// Memory is a Storer that keeps the entries in memory, as a stand-in for tests and local development.
+type Memory struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Record appends the entry, assigning it the next ID.
+func (m *Memory) Record(e Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e.ID = int64(len(m.entries) + 1)
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+// Entries returns the entries selected by the query, newest first.
+func (m *Memory) Entries(q Query) ([]Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Entry, 0)
+	for _, e := range slices.Backward(m.entries) {
+		if len(out) == q.limit() {
+			break
+		}
+		if q.Match(e) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
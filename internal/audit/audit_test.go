@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryFinish(t *testing.T) {
+	e := Start(OpGenerate, SourceAPI)
+	e.Finish(nil)
+	assert.Equal(t, OutcomeSucceeded, e.Outcome)
+	assert.Empty(t, e.Error)
+	assert.False(t, e.FinishedAt.Before(e.StartedAt))
+
+	e.Finish(errors.New("boom"))
+	assert.Equal(t, OutcomeFailed, e.Outcome)
+	assert.Equal(t, "boom", e.Error)
+}
+
+func TestEntrySetConfig(t *testing.T) {
+	e := Start(OpGenerate, SourceAPI)
+	e.SetConfig(map[string]string{"version": "v1"})
+	assert.JSONEq(t, `{"version":"v1"}`, string(e.Config))
+
+	e.SetConfig(make(chan int))
+	assert.JSONEq(t, `{"version":"v1"}`, string(e.Config))
+}
+
+func TestMemoryEntries(t *testing.T) {
+	now := time.Now()
+	m := &Memory{}
+	for _, e := range []Entry{
+		{Operation: OpGenerate, Version: "v1", Actor: "alice", StartedAt: now.Add(-2 * time.Hour)},
+		{Operation: OpPromote, Version: "v1", Actor: "bob", StartedAt: now.Add(-time.Hour)},
+		{Operation: OpDelete, Version: "v2", Actor: "alice", StartedAt: now},
+	} {
+		require.NoError(t, m.Record(e))
+	}
+
+	tests := []struct {
+		name    string
+		query   Query
+		wantIDs []int64
+	}{
+		{name: "All, newest first", query: Query{}, wantIDs: []int64{3, 2, 1}},
+		{name: "Version", query: Query{Version: "v1"}, wantIDs: []int64{2, 1}},
+		{name: "Operation", query: Query{Operation: OpPromote}, wantIDs: []int64{2}},
+		{name: "Actor", query: Query{Actor: "alice"}, wantIDs: []int64{3, 1}},
+		{name: "Since", query: Query{Since: now.Add(-90 * time.Minute)}, wantIDs: []int64{3, 2}},
+		{name: "Limit", query: Query{Limit: 1}, wantIDs: []int64{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Entries(tt.query)
+			require.NoError(t, err)
+			ids := make([]int64, 0, len(got))
+			for _, e := range got {
+				ids = append(ids, e.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
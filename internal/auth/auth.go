@@ -0,0 +1,121 @@
+// Package auth authenticates the users of the admin surfaces (the HTTP API) against the 'users' table,
+// whose passwords are stored as bcrypt hashes. Inactive users are rejected.
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInactiveUser       = errors.New("user is inactive")
+)
+
+// dummyHash is compared against the password of unknown users, so that an unknown username takes
+// as long to reject as a wrong password and response times do not reveal which usernames exist.
+var dummyHash = sync.OnceValue(func() []byte {
+	h, err := bcrypt.GenerateFromPassword([]byte("bb-transform"), bcrypt.DefaultCost)
+	if err != nil {
+		panic(err)
+	}
+	return h
+})
+
+// UserStorer looks up users by username. It returns ErrUserNotFound when there is no such user.
+type UserStorer interface {
+	UserByUsername(username string) (model.Users, error)
+}
+
+// User is an authenticated user.
+type User struct {
+	ID       int32  `json:"id"`
+	Username string `json:"username"`
+}
+
+// Authenticator checks usernames and passwords against the UserStorer.
+type Authenticator struct {
+	log   *slog.Logger
+	store UserStorer
+}
+
+// NewAuthenticator creates a new Authenticator.
+func NewAuthenticator(l *slog.Logger, us UserStorer) *Authenticator {
+	return &Authenticator{
+		log:   l.With("component", "auth"),
+		store: us,
+	}
+}
+
+// Authenticate returns the user with the username if the password matches its hash.
+// It returns ErrInvalidCredentials for unknown users and wrong passwords alike, and ErrInactiveUser
+// for users whose Active flag is not set. Unknown users are still checked against a dummy hash,
+// so that they take as long to reject as wrong passwords.
+func (a *Authenticator) Authenticate(username, password string) (User, error) {
+	u, err := a.store.UserByUsername(username)
+	if errors.Is(err, ErrUserNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, err
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
+		return User{}, ErrInvalidCredentials
+	}
+	if !u.Active {
+		return User{}, ErrInactiveUser
+	}
+	return User{ID: u.ID, Username: u.Username}, nil
+}
+
+// Middleware requires HTTP basic authentication for every request passed to next and
+// stores the authenticated user in the request context (see UserFrom).
+// It responds with 401 Unauthorized for missing or invalid credentials and 403 Forbidden for inactive users.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="bb-transform"`)
+			http.Error(w, ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+			return
+		}
+		u, err := a.Authenticate(username, password)
+		switch {
+		case errors.Is(err, ErrInvalidCredentials):
+			a.log.Warn("authentication failed", "username", username)
+			w.Header().Set("WWW-Authenticate", `Basic realm="bb-transform"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		case errors.Is(err, ErrInactiveUser):
+			a.log.Warn("inactive user rejected", "username", username)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case err != nil:
+			a.log.Error("failed to authenticate", "username", username, "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), u)))
+	})
+}
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying the user.
+func WithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFrom returns the user stored in ctx by Middleware.
+func UserFrom(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userKey{}).(User)
+	return u, ok
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+func hash(t *testing.T, password string) string {
+	t.Helper()
+	h, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return string(h)
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name         string
+		password     string
+		mockBehavior func(storer *MockUserStorer)
+		want         User
+		wantErr      error
+	}{
+		{
+			name:     "Valid credentials",
+			password: "secret",
+			mockBehavior: func(storer *MockUserStorer) {
+				storer.EXPECT().UserByUsername("alice").Return(
+					model.Users{ID: 1, Username: "alice", Password: hash(t, "secret"), Active: true}, nil,
+				)
+			},
+			want: User{ID: 1, Username: "alice"},
+		},
+		{
+			name:     "Wrong password",
+			password: "wrong",
+			mockBehavior: func(storer *MockUserStorer) {
+				storer.EXPECT().UserByUsername("alice").Return(
+					model.Users{ID: 1, Username: "alice", Password: hash(t, "secret"), Active: true}, nil,
+				)
+			},
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:     "Unknown user",
+			password: "secret",
+			mockBehavior: func(storer *MockUserStorer) {
+				storer.EXPECT().UserByUsername("alice").Return(model.Users{}, ErrUserNotFound)
+			},
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:     "Inactive user",
+			password: "secret",
+			mockBehavior: func(storer *MockUserStorer) {
+				storer.EXPECT().UserByUsername("alice").Return(
+					model.Users{ID: 1, Username: "alice", Password: hash(t, "secret")}, nil,
+				)
+			},
+			wantErr: ErrInactiveUser,
+		},
+		{
+			name:     "Store error",
+			password: "secret",
+			mockBehavior: func(storer *MockUserStorer) {
+				storer.EXPECT().UserByUsername("alice").Return(model.Users{}, errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorer := NewMockUserStorer(t)
+			tt.mockBehavior(mockStorer)
+
+			u, err := NewAuthenticator(testLogger(), mockStorer).Authenticate("alice", tt.password)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, u)
+		})
+	}
+}
+
+func TestDummyHash(t *testing.T) {
+	// Unknown users must cost as much as the hashes of real users.
+	cost, err := bcrypt.Cost(dummyHash())
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.DefaultCost, cost)
+	assert.Error(t, bcrypt.CompareHashAndPassword(dummyHash(), []byte("secret")))
+}
+
+func TestMiddleware(t *testing.T) {
+	mockStorer := NewMockUserStorer(t)
+	mockStorer.EXPECT().UserByUsername("alice").Return(
+		model.Users{ID: 1, Username: "alice", Password: hash(t, "secret"), Active: true}, nil,
+	)
+	mockStorer.EXPECT().UserByUsername("bob").Return(
+		model.Users{ID: 2, Username: "bob", Password: hash(t, "secret")}, nil,
+	)
+
+	var got User
+	h := NewAuthenticator(testLogger(), mockStorer).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = UserFrom(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(username, password string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		}
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized, serve("alice", "wrong").Code)
+	assert.Equal(t, http.StatusForbidden, serve("bob", "secret").Code)
+
+	assert.Equal(t, http.StatusNoContent, serve("alice", "secret").Code)
+	assert.Equal(t, User{ID: 1, Username: "alice"}, got)
+}
@@ -0,0 +1,65 @@
+package lambdahandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opplieam/bb-transform/internal/notify"
+)
+
+// Alias actions accepted in a JobAlias message, in addition to ActionList and ActionDelete.
+const (
+	ActionSet     = "set"
+	ActionResolve = "resolve"
+)
+
+// AliasRequest is the body of a JobAlias message.
+// Names is required for set, resolve and delete (resolve and delete use each name in turn),
+// and Version for set.
+type AliasRequest struct {
+	Type    string   `json:"type"`
+	Action  string   `json:"action"`
+	Names   []string `json:"names"`
+	Version string   `json:"version"`
+}
+
+// handleAlias runs a dataset alias action and logs its outcome.
+func (h *Handler) handleAlias(ctx context.Context, body []byte, _ *notify.Event) error {
+	var req AliasRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+
+	switch req.Action {
+	case ActionList:
+		aliases, err := h.aliases.List()
+		if err != nil {
+			return err
+		}
+		for _, v := range aliases {
+			h.log.InfoContext(ctx, "dataset alias", "alias", v.Name, "version", v.Version, "updated_at", v.UpdatedAt)
+		}
+		return nil
+	case ActionSet:
+		return h.aliases.Set(req.Version, req.Names...)
+	case ActionResolve:
+		for _, name := range req.Names {
+			version, err := h.aliases.Resolve(name)
+			if err != nil {
+				return err
+			}
+			h.log.InfoContext(ctx, "resolved alias", "alias", name, "version", version)
+		}
+		return nil
+	case ActionDelete:
+		for _, name := range req.Names {
+			if err := h.aliases.Delete(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownAction, req.Action)
+	}
+}
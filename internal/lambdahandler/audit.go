@@ -0,0 +1,68 @@
+package lambdahandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/opplieam/bb-transform/internal/audit"
+	"github.com/opplieam/bb-transform/internal/notify"
+)
+
+// AuditRecorder appends entries to the audit log, see audit.Storer.
+type AuditRecorder interface {
+	Record(e audit.Entry) error
+}
+
+// auditOperation returns the audit operation of a message, or "" for jobs that are not audited
+// (e.g. stats, exports and listings, which do not change any version).
+func auditOperation(msg message) string {
+	switch msg.Type {
+	case JobGenerate, JobFanOut, JobFinalize:
+		return audit.OpGenerate
+	case JobDeleteVersion:
+		return audit.OpDelete
+	case JobLifecycle:
+		switch msg.action {
+		case ActionDelete:
+			return audit.OpDelete
+		case ActionRetire, ActionArchive, ActionRetention:
+			return audit.OpCleanup
+		}
+	case JobAlias:
+		if msg.action == ActionSet {
+			return audit.OpPromote
+		}
+	}
+	return ""
+}
+
+// recordAudit appends the outcome of a processed message to the audit log.
+// A failure to record is logged and does not fail the job, which has already run.
+func (h *Handler) recordAudit(ctx context.Context, op string, msg message, ev notify.Event, start time.Time) {
+	if h.audit == nil {
+		return
+	}
+	outcome := audit.OutcomeSucceeded
+	switch ev.Status {
+	case notify.StatusFailed:
+		outcome = audit.OutcomeFailed
+	case notify.StatusContinued:
+		outcome = audit.OutcomeContinued
+	}
+	e := audit.Entry{
+		Operation:  op,
+		Version:    ev.Version,
+		Actor:      msg.actor,
+		MessageID:  ev.MessageID,
+		Source:     audit.SourceLambda,
+		Config:     msg.Payload,
+		Outcome:    outcome,
+		Error:      ev.Error,
+		StartedAt:  start,
+		FinishedAt: ev.FinishedAt,
+		DurationMS: ev.DurationMS,
+	}
+	if err := h.audit.Record(e); err != nil {
+		h.log.WarnContext(ctx, "failed to record audit entry", "message_id", ev.MessageID, "operation", op, "error", err)
+	}
+}
@@ -0,0 +1,59 @@
+package lambdahandler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/opplieam/bb-transform/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditOperation(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  message
+		want string
+	}{
+		{name: "Generate", msg: message{Envelope: Envelope{Type: JobGenerate}}, want: audit.OpGenerate},
+		{name: "Fan-out", msg: message{Envelope: Envelope{Type: JobFanOut}}, want: audit.OpGenerate},
+		{name: "Delete version", msg: message{Envelope: Envelope{Type: JobDeleteVersion}}, want: audit.OpDelete},
+		{name: "Lifecycle delete", msg: message{Envelope: Envelope{Type: JobLifecycle}, action: ActionDelete}, want: audit.OpDelete},
+		{name: "Retention", msg: message{Envelope: Envelope{Type: JobLifecycle}, action: ActionRetention}, want: audit.OpCleanup},
+		{name: "Alias set", msg: message{Envelope: Envelope{Type: JobAlias}, action: ActionSet}, want: audit.OpPromote},
+		{name: "Lifecycle list", msg: message{Envelope: Envelope{Type: JobLifecycle}, action: ActionList}},
+		{name: "Stats", msg: message{Envelope: Envelope{Type: JobStats}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, auditOperation(tt.msg))
+		})
+	}
+}
+
+func TestHandleSQSEventAudit(t *testing.T) {
+	mem := &audit.Memory{}
+	h := NewHandler(testLogger(), nil, nil, nil, nil, nil, mem)
+
+	err := h.HandleSQSEvent(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "m-1", Body: `{"type":"finalize","schema_version":1,"payload":{"actor":"alice"}}`},
+	}})
+	assert.ErrorIs(t, err, ErrMissingVersion)
+	err = h.HandleSQSEvent(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "m-2", Body: `{"type":"stats","schema_version":1,"payload":{}}`},
+	}})
+	assert.ErrorIs(t, err, ErrMissingVersion)
+
+	entries, err := mem.Entries(audit.Query{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, audit.OpGenerate, entries[0].Operation)
+	assert.Equal(t, "alice", entries[0].Actor)
+	assert.Equal(t, "m-1", entries[0].MessageID)
+	assert.Equal(t, audit.SourceLambda, entries[0].Source)
+	assert.Equal(t, audit.OutcomeFailed, entries[0].Outcome)
+	assert.Equal(t, ErrMissingVersion.Error(), entries[0].Error)
+	assert.JSONEq(t, `{"actor":"alice"}`, string(entries[0].Config))
+}
@@ -0,0 +1,106 @@
+package lambdahandler
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opplieam/bb-transform/internal/notify"
+	"github.com/opplieam/bb-transform/internal/transform"
+)
+
+// FanOutRequest is the payload of a JobFanOut message: the generate payload (a transform.Config
+// or a named pipeline with overrides) and the number of partition jobs to split it into.
+// Actor, when set, overrides the actor of the config and is passed on to every partition and to the finalize job.
+type FanOutRequest struct {
+	Partitions int             `json:"partitions"`
+	Config     json.RawMessage `json:"config"`
+	Actor      string          `json:"actor"`
+}
+
+// FinalizeRequest is the payload of a JobFinalize message.
+type FinalizeRequest struct {
+	Version string   `json:"version"`
+	Aliases []string `json:"aliases"`
+	Actor   string   `json:"actor"`
+}
+
+// handleFanOut partitions a generation with transform.Transform.FanOut and sends one JobGenerate message
+// per partition to the transform queue. Each partition is then handled by its own invocation,
+// and the last one to complete sends the JobFinalize message.
+// The partitions are recorded under the SQS message ID, so a redelivered fan-out message sends the messages
+// of the same partitions again instead of starting over; the ones that already completed are skipped.
+func (h *Handler) handleFanOut(ctx context.Context, payload []byte, ev *notify.Event) error {
+	if h.queue == nil {
+		return ErrNoQueue
+	}
+	req, cfg, err := h.decodeFanOut(payload)
+	if err != nil {
+		return err
+	}
+
+	t := transform.NewTransform(h.log, h.cs, cfg)
+	parts, err := t.FanOut(ctx, ev.MessageID, req.Partitions)
+	ev.Version = t.Version()
+	if err != nil {
+		return err
+	}
+	return h.enqueuePartitions(ctx, ev.MessageID, parts)
+}
+
+// decodeFanOut reads a JobFanOut payload and resolves its config, which carries the actor of the request.
+func (h *Handler) decodeFanOut(payload []byte) (FanOutRequest, transform.Config, error) {
+	var req FanOutRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return FanOutRequest{}, transform.Config{}, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	cfg, err := h.resolver.Resolve(req.Config)
+	if err != nil {
+		return FanOutRequest{}, transform.Config{}, err
+	}
+	cfg.Actor = cmp.Or(req.Actor, cfg.Actor)
+	return req, cfg, nil
+}
+
+// enqueuePartitions sends a JobGenerate message for every partition config.
+// The idempotency key of a partition is derived from the fan-out message, so a redelivered fan-out message
+// does not generate the same partition twice.
+func (h *Handler) enqueuePartitions(ctx context.Context, fanOutID string, parts []transform.Config) error {
+	for _, cfg := range parts {
+		key := fmt.Sprintf("%s:partition-%d", fanOutID, cfg.Partition.Index)
+		if err := h.enqueue(ctx, JobGenerate, cfg, key); err != nil {
+			return err
+		}
+	}
+	h.log.InfoContext(ctx, "enqueued partitions", "partitions", len(parts))
+	return nil
+}
+
+// enqueueFinalize sends the JobFinalize message of a fan-out generation.
+func (h *Handler) enqueueFinalize(ctx context.Context, req FinalizeRequest) error {
+	if err := h.enqueue(ctx, JobFinalize, req, ""); err != nil {
+		return err
+	}
+	h.log.InfoContext(ctx, "enqueued finalize", "version", req.Version)
+	return nil
+}
+
+// handleFinalize marks a fan-out generation as ready once all of its partitions have completed.
+// It fails with transform.ErrPartitionsIncomplete while a partition is pending, so SQS retries it later.
+func (h *Handler) handleFinalize(ctx context.Context, payload []byte, ev *notify.Event) error {
+	var req FinalizeRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	if req.Version == "" {
+		return ErrMissingVersion
+	}
+	ev.Version = req.Version
+	t := transform.NewTransform(h.log, h.cs, transform.Config{Version: req.Version, Aliases: req.Aliases, Actor: req.Actor})
+	if err := t.Finalize(ctx); err != nil {
+		return err
+	}
+	h.log.InfoContext(ctx, "dataset finalized", "version", req.Version)
+	return nil
+}
@@ -0,0 +1,86 @@
+package lambdahandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/opplieam/bb-transform/internal/notify"
+	"github.com/opplieam/bb-transform/internal/queue"
+	"github.com/opplieam/bb-transform/internal/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnqueuePartitions(t *testing.T) {
+	q := &queue.Memory{}
+	h := NewHandler(testLogger(), nil, nil, nil, nil, q, nil)
+	parts := []transform.Config{
+		{Version: "v1", Seed: 10, Actor: "alice", Partition: &transform.Partition{Index: 0, AfterID: 0, UpToID: 5}},
+		{Version: "v1", Seed: 11, Actor: "alice", Partition: &transform.Partition{Index: 1, AfterID: 5, UpToID: 10}},
+	}
+	require.NoError(t, h.enqueuePartitions(context.Background(), "msg-1", parts))
+	require.NoError(t, h.enqueueFinalize(context.Background(), FinalizeRequest{Version: "v1", Aliases: []string{transform.AliasLatest}, Actor: "alice"}))
+
+	messages := q.Messages()
+	require.Len(t, messages, 3)
+	for i, body := range messages[:2] {
+		msg, err := parseMessage(body)
+		require.NoError(t, err)
+		assert.Equal(t, JobGenerate, msg.Type)
+		assert.Equal(t, transform.PartitionLockKey("v1", i), msg.version)
+		assert.Equal(t, fmt.Sprintf("msg-1:partition-%d", i), msg.IdempotencyKey)
+		assert.Equal(t, "alice", msg.actor)
+
+		cfg, err := h.resolver.Resolve(msg.Payload)
+		require.NoError(t, err)
+		assert.Equal(t, parts[i], cfg)
+	}
+
+	msg, err := parseMessage(messages[2])
+	require.NoError(t, err)
+	assert.Equal(t, JobFinalize, msg.Type)
+	var req FinalizeRequest
+	require.NoError(t, json.Unmarshal(msg.Payload, &req))
+	assert.Equal(t, FinalizeRequest{Version: "v1", Aliases: []string{transform.AliasLatest}, Actor: "alice"}, req)
+}
+
+func TestDecodeFanOutActor(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{name: "Request", payload: `{"partitions":2,"actor":"alice","config":{"version":"v1"}}`, want: "alice"},
+		{name: "Config", payload: `{"partitions":2,"config":{"version":"v1","actor":"bob"}}`, want: "bob"},
+		{name: "Request overrides config", payload: `{"partitions":2,"actor":"alice","config":{"version":"v1","actor":"bob"}}`, want: "alice"},
+		{name: "None", payload: `{"partitions":2,"config":{"version":"v1"}}`},
+	}
+
+	h := NewHandler(testLogger(), nil, nil, nil, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cfg, err := h.decodeFanOut([]byte(tt.payload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Actor)
+
+			// The fan-out job itself is audited with the same actor.
+			msg, err := parseMessage([]byte(`{"type":"fan-out","schema_version":1,"payload":` + tt.payload + `}`))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, msg.actor)
+		})
+	}
+}
+
+func TestHandleFanOutWithoutQueue(t *testing.T) {
+	h := NewHandler(testLogger(), nil, nil, nil, nil, nil, nil)
+	err := h.handleFanOut(context.Background(), []byte(`{"partitions":2,"config":{"version":"v1"}}`), &notify.Event{})
+	assert.ErrorIs(t, err, ErrNoQueue)
+}
+
+func TestHandleFinalizeMissingVersion(t *testing.T) {
+	h := NewHandler(testLogger(), nil, nil, nil, nil, nil, nil)
+	err := h.handleFinalize(context.Background(), []byte(`{"aliases":["latest"]}`), &notify.Event{})
+	assert.ErrorIs(t, err, ErrMissingVersion)
+}
@@ -0,0 +1,193 @@
+package lambdahandler
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/opplieam/bb-transform/internal/notify"
+	"github.com/opplieam/bb-transform/internal/transform"
+)
+
+// SchemaVersion is the newest envelope schema version understood by the handler.
+const SchemaVersion = 1
+
+var (
+	ErrUnsupportedSchema = errors.New("unsupported message schema version")
+)
+
+// Envelope is the versioned message format: the job type, the schema version of the envelope
+// and the job-specific payload. IdempotencyKey is optional, see HandleSQSEvent.
+//
+// Messages without a payload field are legacy messages: the whole body is the payload and
+// the job type is read from its "type" field (JobGenerate when omitted), so a bare transform.Config is still accepted.
+type Envelope struct {
+	Type           string          `json:"type"`
+	SchemaVersion  int             `json:"schema_version"`
+	Payload        json.RawMessage `json:"payload"`
+	IdempotencyKey string          `json:"idempotency_key"`
+}
+
+// message is a parsed message body.
+type message struct {
+	Envelope
+	// version is the "version" field of the payload, used to serialize jobs for the same version.
+	// For the partition jobs of a fan-out generation it is the transform.PartitionLockKey,
+	// so that the partitions of a version run in parallel.
+	version string
+	// action and actor are the "action" and "actor" fields of the payload, recorded in the audit log.
+	// The actor of a fan-out message may also be set in its "config".
+	action string
+	actor  string
+}
+
+// parseMessage reads an envelope or legacy message body.
+func parseMessage(body []byte) (message, error) {
+	var m message
+	if err := json.Unmarshal(body, &m.Envelope); err != nil {
+		return message{}, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	if len(m.Payload) == 0 || bytes.Equal(m.Payload, []byte("null")) {
+		m.Payload = body
+	} else if m.SchemaVersion > SchemaVersion {
+		return message{}, fmt.Errorf("%w: %d", ErrUnsupportedSchema, m.SchemaVersion)
+	}
+	m.Type = cmp.Or(m.Type, JobGenerate)
+
+	var payload struct {
+		Version   string               `json:"version"`
+		Partition *transform.Partition `json:"partition"`
+		Action    string               `json:"action"`
+		Actor     string               `json:"actor"`
+		Config    struct {
+			Actor string `json:"actor"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(m.Payload, &payload); err != nil {
+		return message{}, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	m.version, m.action, m.actor = payload.Version, payload.Action, cmp.Or(payload.Actor, payload.Config.Actor)
+	if payload.Partition != nil && payload.Version != "" {
+		m.version = transform.PartitionLockKey(payload.Version, payload.Partition.Index)
+	}
+	return m, nil
+}
+
+// jobHandler runs a job from its payload and records its version and row counts in ev.
+type jobHandler func(h *Handler, ctx context.Context, payload []byte, ev *notify.Event) error
+
+// jobRegistry maps job types to their handlers. New job types are added here.
+var jobRegistry = map[string]jobHandler{
+	JobGenerate:      (*Handler).generate,
+	JobLifecycle:     (*Handler).handleLifecycle,
+	JobAlias:         (*Handler).handleAlias,
+	JobExport:        (*Handler).handleExport,
+	JobDeleteVersion: (*Handler).handleDeleteVersion,
+	JobStats:         (*Handler).handleStats,
+	JobValidateData:  (*Handler).handleValidateData,
+	JobFanOut:        (*Handler).handleFanOut,
+	JobFinalize:      (*Handler).handleFinalize,
+}
+
+// VersionRequest is the payload of JobDeleteVersion, JobStats and JobValidateData messages.
+// Force allows JobDeleteVersion to delete an immutable version or one an alias points to.
+type VersionRequest struct {
+	Version string `json:"version"`
+	Force   bool   `json:"force"`
+}
+
+// ExportRequest is the payload of a JobExport message. Format defaults to transform.ExportFormatJSONL
+// and an empty Label exports every split.
+type ExportRequest struct {
+	Version string `json:"version"`
+	Format  string `json:"format"`
+	Label   string `json:"label"`
+}
+
+func decodeVersionRequest(payload []byte) (VersionRequest, error) {
+	var req VersionRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return VersionRequest{}, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	if req.Version == "" {
+		return VersionRequest{}, ErrMissingVersion
+	}
+	return req, nil
+}
+
+// handleExport writes the rows of a version to a file in the directory named by ArchiveDirEnv.
+func (h *Handler) handleExport(ctx context.Context, payload []byte, ev *notify.Event) error {
+	var req ExportRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	if req.Version == "" {
+		return ErrMissingVersion
+	}
+	ev.Version = req.Version
+	path, err := h.lifecycle.Export(req.Version, cmp.Or(req.Format, transform.ExportFormatJSONL), req.Label)
+	if err != nil {
+		return err
+	}
+	h.log.InfoContext(ctx, "exported dataset", "version", req.Version, "path", path)
+	return nil
+}
+
+// handleDeleteVersion removes a version, its rows, its taxonomy snapshot and its aliases.
+func (h *Handler) handleDeleteVersion(_ context.Context, payload []byte, ev *notify.Event) error {
+	req, err := decodeVersionRequest(payload)
+	if err != nil {
+		return err
+	}
+	ev.Version = req.Version
+	return h.lifecycle.Delete(req.Version, req.Force)
+}
+
+// handleStats logs the status and row counts of a version and reports the counts in ev.
+func (h *Handler) handleStats(ctx context.Context, payload []byte, ev *notify.Event) error {
+	req, err := decodeVersionRequest(payload)
+	if err != nil {
+		return err
+	}
+	ev.Version = req.Version
+	stats, err := h.lifecycle.Stats(req.Version)
+	if err != nil {
+		return err
+	}
+	ev.Counts = make(map[string]int, len(stats.Labels))
+	for label, rows := range stats.Labels {
+		ev.Counts[label] = int(rows)
+	}
+	h.log.InfoContext(ctx, "dataset stats",
+		"version", stats.Version, "status", stats.Status, "immutable", stats.Immutable,
+		"rows", stats.Rows, "labels", stats.Labels, "created_at", stats.CreatedAt,
+	)
+	return nil
+}
+
+// handleValidateData checks the rows of a version against the current taxonomy
+// and fails with transform.ErrInvalidData if any problem is found.
+func (h *Handler) handleValidateData(ctx context.Context, payload []byte, ev *notify.Event) error {
+	req, err := decodeVersionRequest(payload)
+	if err != nil {
+		return err
+	}
+	ev.Version = req.Version
+	rows, err := h.cs.Dataset(req.Version)
+	if err != nil {
+		return err
+	}
+	original, err := h.cs.OriginalCategory()
+	if err != nil {
+		return err
+	}
+	report := transform.ValidateData(rows, original)
+	h.log.InfoContext(ctx, "validated dataset", "version", req.Version, "report", report)
+	if !report.Valid() {
+		return fmt.Errorf("%w: %+v", transform.ErrInvalidData, report)
+	}
+	return nil
+}
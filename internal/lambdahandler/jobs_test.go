@@ -0,0 +1,88 @@
+package lambdahandler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantType    string
+		wantVersion string
+		wantKey     string
+		wantPayload string
+		wantErr     error
+	}{
+		{
+			name:        "Legacy config",
+			body:        `{"version":"v1","shuffle":true}`,
+			wantType:    JobGenerate,
+			wantVersion: "v1",
+			wantPayload: `{"version":"v1","shuffle":true}`,
+		},
+		{
+			name:        "Legacy lifecycle",
+			body:        `{"type":"lifecycle","action":"retire","version":"v1"}`,
+			wantType:    JobLifecycle,
+			wantVersion: "v1",
+			wantPayload: `{"type":"lifecycle","action":"retire","version":"v1"}`,
+		},
+		{
+			name:        "Envelope",
+			body:        `{"type":"stats","schema_version":1,"idempotency_key":"k-1","payload":{"version":"v2"}}`,
+			wantType:    JobStats,
+			wantVersion: "v2",
+			wantKey:     "k-1",
+			wantPayload: `{"version":"v2"}`,
+		},
+		{
+			name:        "Partition",
+			body:        `{"type":"generate","schema_version":1,"payload":{"version":"v2","partition":{"index":3}}}`,
+			wantType:    JobGenerate,
+			wantVersion: "v2#partition-3",
+			wantPayload: `{"version":"v2","partition":{"index":3}}`,
+		},
+		{
+			name:    "Unsupported schema version",
+			body:    `{"type":"stats","schema_version":2,"payload":{"version":"v2"}}`,
+			wantErr: ErrUnsupportedSchema,
+		},
+		{
+			name:    "Invalid payload",
+			body:    `{"type":"stats","payload":"v2"}`,
+			wantErr: ErrUnmarshalConfig,
+		},
+		{
+			name:    "Invalid body",
+			body:    `not json`,
+			wantErr: ErrUnmarshalConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := parseMessage([]byte(tt.body))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, msg.Type)
+			assert.Equal(t, tt.wantVersion, msg.version)
+			assert.Equal(t, tt.wantKey, msg.IdempotencyKey)
+			assert.JSONEq(t, tt.wantPayload, string(msg.Payload))
+		})
+	}
+}
+
+func TestJobRegistry(t *testing.T) {
+	for _, jobType := range []string{
+		JobGenerate, JobLifecycle, JobAlias, JobExport, JobDeleteVersion, JobStats, JobValidateData,
+	} {
+		assert.Contains(t, jobRegistry, jobType)
+	}
+}
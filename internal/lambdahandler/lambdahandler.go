@@ -7,57 +7,236 @@
 package lambdahandler
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/opplieam/bb-transform/internal/notify"
+	"github.com/opplieam/bb-transform/internal/queue"
 	"github.com/opplieam/bb-transform/internal/store"
 	"github.com/opplieam/bb-transform/internal/transform"
 )
 
 var (
 	ErrUnmarshalConfig = errors.New("failed to unmarshal lambda config")
+	ErrUnknownJobType  = errors.New("unknown job type")
+	ErrNoQueue         = errors.New("no transform queue configured")
+)
+
+// JobLease is how long a running job blocks redeliveries of its message and other jobs for the same version.
+// It is well above the Lambda timeout; a job cut off by the timeout stays marked as running until its lease expires.
+const JobLease = 15 * time.Minute
+
+// JobLedger records job messages by idempotency key.
+// StartJob returns store.ErrJobDone for jobs that already succeeded, and store.ErrJobInProgress or
+// store.ErrVersionBusy when the job or another job for the same version is running within its lease.
+type JobLedger interface {
+	StartJob(key, messageID, jobType, version string, lease time.Duration) error
+	FinishJob(key, status, errMsg string) error
+}
+
+// ArchiveDirEnv is the environment variable holding the directory version archives are written to.
+const ArchiveDirEnv = "ARCHIVE_DIR"
+
+// Job types selected by the "type" field of a message, see Envelope. Messages without a type generate a dataset.
+const (
+	JobGenerate      = "generate"
+	JobLifecycle     = "lifecycle"
+	JobAlias         = "alias"
+	JobExport        = "export"
+	JobDeleteVersion = "delete-version"
+	JobStats         = "stats"
+	JobValidateData  = "validate-data"
+	JobFanOut        = "fan-out"
+	JobFinalize      = "finalize"
 )
 
 // Handler provides a struct to encapsulate the dependencies and methods required to handle SQS events.
 // It includes a logger for logging and a CategoryStore for database interactions.
 type Handler struct {
-	log *slog.Logger
-	cs  *store.CategoryStore
+	log       *slog.Logger
+	cs        *store.CategoryStore
+	resolver  *PipelineResolver
+	lifecycle *transform.Lifecycle
+	aliases   *transform.Aliases
+	notifier  notify.Notifier
+	ledger    JobLedger
+	queue     queue.Sender
+	audit     AuditRecorder
 }
 
 // NewHandler creates a new instance of Handler.
-// It takes a CategoryStore instance as a dependency and initializes the logger with a component tag.
+// It takes a CategoryStore and a PipelineStore instance as dependencies and initializes the logger with a component tag.
+// Jobs are recorded in jl, which may be nil to process every message without deduplication.
+// Job results are published to n; a nil Notifier drops them.
+// Continuations of generations that ran out of time are sent to q; with a nil Sender such a generation fails.
+// Generate, cleanup, delete and promote jobs are recorded in al, which may be nil to keep no audit log.
+// Version archives are written to the directory named by ArchiveDirEnv.
 // Returns a pointer to the created Handler.
-func NewHandler(l *slog.Logger, cs *store.CategoryStore) *Handler {
+func NewHandler(
+	l *slog.Logger, cs *store.CategoryStore, ps *store.PipelineStore, jl JobLedger, n notify.Notifier, q queue.Sender, al AuditRecorder,
+) *Handler {
+	if n == nil {
+		n = notify.Nop{}
+	}
 	return &Handler{
-		log: l.With("component", "lambda"),
-		cs:  cs,
+		log:       l.With("component", "lambda"),
+		cs:        cs,
+		resolver:  NewPipelineResolver(ps),
+		lifecycle: transform.NewLifecycle(l, cs, os.Getenv(ArchiveDirEnv)),
+		aliases:   transform.NewAliases(l, cs),
+		notifier:  n,
+		ledger:    jl,
+		queue:     q,
+		audit:     al,
 	}
 }
 
-// HandleSQSEvent processes an SQS event containing configuration data for generating a dataset.
-// It iterates through each SQS message, unmarshal the message body into a transform.Config,
-// creates a new Transform instance with the unmarshalled configuration, and triggers the dataset generation process.
+// HandleSQSEvent processes an SQS event containing job messages.
+// It iterates through each SQS message, reads it as an Envelope (or a legacy message, e.g. a bare transform.Config)
+// and dispatches its payload to the handler registered for its type in jobRegistry.
+// Every message is recorded in the JobLedger under its "idempotency_key" field (or its SQS message ID),
+// so redelivered messages of succeeded jobs are skipped and jobs for a version that is already being processed
+// are rejected for a later retry.
+// The outcome of every processed message is published to the Notifier as a notify.Event,
+// and the outcome of audited jobs (see auditOperation) is appended to the audit log.
 // Logs messages for tracking the start and completion of processing each message.
-// Returns an error if unmarshalling the configuration or generating the dataset fails.
+// Returns an error if a message cannot be unmarshalled, has an unknown type, cannot be started, or its job fails.
 func (h *Handler) HandleSQSEvent(ctx context.Context, sqsEvent events.SQSEvent) error {
 	for _, record := range sqsEvent.Records {
-		h.log.InfoContext(ctx, "processing message", "message_id", record.MessageId)
-		var cfg transform.Config
-		if err := json.Unmarshal([]byte(record.Body), &cfg); err != nil {
-			h.log.ErrorContext(ctx, "failed to unmarshal config", "error", err)
-			return ErrUnmarshalConfig
+		if err := h.handleMessage(ctx, record); err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+func (h *Handler) handleMessage(ctx context.Context, record events.SQSMessage) error {
+	h.log.InfoContext(ctx, "processing message", "message_id", record.MessageId)
+	start := time.Now()
+	ev := notify.Event{MessageID: record.MessageId, Status: notify.StatusSucceeded}
+
+	msg, err := parseMessage([]byte(record.Body))
+	if err != nil {
+		h.log.ErrorContext(ctx, "failed to unmarshal message", "error", err)
+	} else {
+		ev.Type = msg.Type
+		ev.Version = msg.version
+		key := cmp.Or(msg.IdempotencyKey, record.MessageId)
+		if h.ledger != nil {
+			if lErr := h.ledger.StartJob(key, record.MessageId, msg.Type, msg.version, JobLease); lErr != nil {
+				if errors.Is(lErr, store.ErrJobDone) {
+					h.log.InfoContext(ctx, "skipping duplicate message", "message_id", record.MessageId, "idempotency_key", key)
+					return nil
+				}
+				h.log.WarnContext(ctx, "job not started", "message_id", record.MessageId, "idempotency_key", key, "error", lErr)
+				return lErr
+			}
+		}
+
+		err = h.dispatch(ctx, msg, &ev)
+		if h.ledger != nil {
+			status, errMsg := store.JobStatusSucceeded, ""
+			if err != nil {
+				status, errMsg = store.JobStatusFailed, err.Error()
+			}
+			if fErr := h.ledger.FinishJob(key, status, errMsg); fErr != nil {
+				h.log.WarnContext(ctx, "failed to record job result", "idempotency_key", key, "error", fErr)
+			}
+		}
+	}
+
+	if err != nil {
+		h.log.ErrorContext(ctx, "failed to process message", "type", ev.Type, "error", err)
+		ev.Status = notify.StatusFailed
+		ev.Error = err.Error()
+	}
+	ev.FinishedAt = time.Now()
+	ev.DurationMS = ev.FinishedAt.Sub(start).Milliseconds()
+	if op := auditOperation(msg); op != "" {
+		h.recordAudit(ctx, op, msg, ev, start)
+	}
+	if nErr := h.notifier.Notify(ctx, ev); nErr != nil {
+		h.log.WarnContext(ctx, "failed to publish job result", "message_id", record.MessageId, "error", nErr)
+	}
+	return err
+}
+
+// dispatch runs the handler registered for the message type on its payload.
+func (h *Handler) dispatch(ctx context.Context, msg message, ev *notify.Event) error {
+	run, ok := jobRegistry[msg.Type]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJobType, msg.Type)
+	}
+	return run(h, ctx, msg.Payload, ev)
+}
+
+// generate resolves the message body into a transform.Config
+// (either a complete config or a named pipeline definition with overrides, see PipelineResolver),
+// creates a new Transform instance with the configuration, and triggers the dataset generation process.
+// When the generation stops near the Lambda deadline, a continuation message resuming from the checkpoint
+// is sent to the transform queue and the job is reported as notify.StatusContinued.
+// When the last partition of a fan-out generation completes, a JobFinalize message is sent for its version.
+// The summary of the run is logged by transform.Transform.GenerateDataset.
+func (h *Handler) generate(ctx context.Context, body []byte, ev *notify.Event) error {
+	cfg, err := h.resolver.Resolve(body)
+	if err != nil {
+		h.log.ErrorContext(ctx, "failed to resolve config", "error", err)
+		return err
+	}
 
-		t := transform.NewTransform(h.log, h.cs, cfg)
-		if err := t.GenerateDataset(); err != nil {
-			h.log.ErrorContext(ctx, "failed to generate dataset", "error", err)
+	t := transform.NewTransform(h.log, h.cs, cfg)
+	result, err := t.GenerateDataset(ctx)
+	ev.Version = t.Version()
+	if errors.Is(err, transform.ErrDeadlineReached) && h.queue != nil {
+		if err = h.continueGeneration(ctx, t); err != nil {
+			h.log.ErrorContext(ctx, "failed to enqueue continuation", "version", t.Version(), "error", err)
 			return err
 		}
-		h.log.InfoContext(ctx, "dataset generated successfully", "version", cfg.Version)
+		ev.Status = notify.StatusContinued
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ev.Counts = result.Counts
+	if !t.FanInReady() {
+		return nil
 	}
+	if h.queue == nil {
+		h.log.WarnContext(ctx, "all partitions completed, send a finalize message", "version", t.Version())
+		return nil
+	}
+	return h.enqueueFinalize(ctx, FinalizeRequest{Version: t.Version(), Aliases: cfg.Aliases, Actor: cfg.Actor})
+}
+
+// continueGeneration sends a generate message that resumes the interrupted generation from its checkpoint.
+func (h *Handler) continueGeneration(ctx context.Context, t *transform.Transform) error {
+	if err := h.enqueue(ctx, JobGenerate, t.Continuation(), ""); err != nil {
+		return err
+	}
+	h.log.InfoContext(ctx, "enqueued continuation", "version", t.Version())
 	return nil
 }
+
+// enqueue sends a job message with the payload to the transform queue.
+func (h *Handler) enqueue(ctx context.Context, jobType string, payload any, idempotencyKey string) error {
+	if h.queue == nil {
+		return ErrNoQueue
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(Envelope{Type: jobType, SchemaVersion: SchemaVersion, Payload: b, IdempotencyKey: idempotencyKey})
+	if err != nil {
+		return err
+	}
+	return h.queue.Send(ctx, body)
+}
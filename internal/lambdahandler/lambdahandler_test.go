@@ -0,0 +1,105 @@
+package lambdahandler
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/opplieam/bb-transform/internal/notify"
+	"github.com/opplieam/bb-transform/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+func TestHandleSQSEventNotifiesFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantErr  error
+		wantType string
+	}{
+		{name: "Unknown job type", body: `{"type":"magic"}`, wantErr: ErrUnknownJobType, wantType: "magic"},
+		{name: "Invalid body", body: `not json`, wantErr: ErrUnmarshalConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := &notify.Memory{}
+			h := NewHandler(testLogger(), nil, nil, nil, mem, nil, nil)
+
+			err := h.HandleSQSEvent(context.Background(), events.SQSEvent{
+				Records: []events.SQSMessage{{MessageId: "m-1", Body: tt.body}},
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			got := mem.Events()
+			require.Len(t, got, 1)
+			assert.Equal(t, "m-1", got[0].MessageID)
+			assert.Equal(t, tt.wantType, got[0].Type)
+			assert.Equal(t, notify.StatusFailed, got[0].Status)
+			assert.NotEmpty(t, got[0].Error)
+		})
+	}
+}
+
+func TestHandleSQSEventLedger(t *testing.T) {
+	type mockBehavior func(ledger *MockJobLedger)
+	tests := []struct {
+		name         string
+		body         string
+		mockBehavior mockBehavior
+		wantErr      error
+		wantEvents   int
+	}{
+		{
+			name: "Duplicate message is skipped",
+			body: `{"version":"v1"}`,
+			mockBehavior: func(ledger *MockJobLedger) {
+				ledger.EXPECT().StartJob("m-1", "m-1", JobGenerate, "v1", JobLease).Return(store.ErrJobDone)
+			},
+		},
+		{
+			name: "Version busy",
+			body: `{"version":"v1","idempotency_key":"k-1"}`,
+			mockBehavior: func(ledger *MockJobLedger) {
+				ledger.EXPECT().StartJob("k-1", "m-1", JobGenerate, "v1", JobLease).Return(store.ErrVersionBusy)
+			},
+			wantErr: store.ErrVersionBusy,
+		},
+		{
+			name: "Failed job is recorded",
+			body: `{"type":"magic","idempotency_key":"k-1"}`,
+			mockBehavior: func(ledger *MockJobLedger) {
+				ledger.EXPECT().StartJob("k-1", "m-1", "magic", "", JobLease).Return(nil)
+				ledger.EXPECT().FinishJob("k-1", store.JobStatusFailed, "unknown job type: magic").Return(nil)
+			},
+			wantErr:    ErrUnknownJobType,
+			wantEvents: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := NewMockJobLedger(t)
+			tt.mockBehavior(ledger)
+			mem := &notify.Memory{}
+			h := NewHandler(testLogger(), nil, nil, ledger, mem, nil, nil)
+
+			err := h.HandleSQSEvent(context.Background(), events.SQSEvent{
+				Records: []events.SQSMessage{{MessageId: "m-1", Body: tt.body}},
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, mem.Events(), tt.wantEvents)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package lambdahandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/opplieam/bb-transform/internal/notify"
+	"github.com/opplieam/bb-transform/internal/transform"
+)
+
+// Lifecycle actions accepted in a JobLifecycle message.
+const (
+	ActionList      = "list"
+	ActionProtect   = "protect"
+	ActionUnprotect = "unprotect"
+	ActionRetire    = "retire"
+	ActionArchive   = "archive"
+	ActionDelete    = "delete"
+	ActionRetention = "retention"
+)
+
+var (
+	ErrUnknownAction  = errors.New("unknown lifecycle action")
+	ErrMissingVersion = errors.New("version is required")
+	ErrMissingPolicy  = errors.New("retention policy is required")
+)
+
+// LifecycleRequest is the body of a JobLifecycle message.
+// Version is required for protect, unprotect, retire, archive and delete, and Retention for the retention action.
+// Force allows archive and delete to remove an immutable version or one an alias points to.
+type LifecycleRequest struct {
+	Type      string                     `json:"type"`
+	Action    string                     `json:"action"`
+	Version   string                     `json:"version"`
+	Retention *transform.RetentionPolicy `json:"retention"`
+	Force     bool                       `json:"force"`
+}
+
+// handleLifecycle runs a dataset version lifecycle action and logs its outcome.
+func (h *Handler) handleLifecycle(ctx context.Context, body []byte, _ *notify.Event) error {
+	var req LifecycleRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+
+	switch req.Action {
+	case ActionList:
+		versions, err := h.lifecycle.List()
+		if err != nil {
+			return err
+		}
+		for _, v := range versions {
+			h.log.InfoContext(ctx, "dataset version",
+				"version", v.Version, "status", v.Status, "immutable", v.Immutable, "rows", v.Rows, "labels", v.Labels, "created_at", v.CreatedAt,
+			)
+		}
+		return nil
+	case ActionRetention:
+		if req.Retention == nil {
+			return ErrMissingPolicy
+		}
+		_, err := h.lifecycle.ApplyRetention(*req.Retention)
+		return err
+	case ActionProtect, ActionUnprotect, ActionRetire, ActionArchive, ActionDelete:
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownAction, req.Action)
+	}
+
+	if req.Version == "" {
+		return ErrMissingVersion
+	}
+	switch req.Action {
+	case ActionProtect:
+		return h.lifecycle.Protect(req.Version)
+	case ActionUnprotect:
+		return h.lifecycle.Unprotect(req.Version)
+	case ActionRetire:
+		return h.lifecycle.Retire(req.Version)
+	case ActionArchive:
+		_, err := h.lifecycle.Archive(req.Version, req.Force)
+		return err
+	default:
+		return h.lifecycle.Delete(req.Version, req.Force)
+	}
+}
@@ -0,0 +1,128 @@
+package lambdahandler
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/opplieam/bb-transform/internal/store"
+	"github.com/opplieam/bb-transform/internal/transform"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed pipelines/*.yaml
+var embeddedPipelines embed.FS
+
+var (
+	ErrPipelineNotFound = errors.New("pipeline definition not found")
+	ErrInvalidPipeline  = errors.New("invalid pipeline definition")
+	ErrInvalidConfig    = errors.New("invalid transform config")
+)
+
+// pipelineKey is the message field referencing a named pipeline definition.
+const pipelineKey = "pipeline"
+
+// DefinitionStorer looks up named pipeline definitions stored outside the binary.
+// It returns store.ErrNotFound when the name is unknown.
+type DefinitionStorer interface {
+	PipelineDefinition(name string) ([]byte, error)
+}
+
+// PipelineResolver turns an SQS message body into a transform.Config.
+// A body may reference a named pipeline definition with the "pipeline" field; the definition is looked up
+// in the DefinitionStorer first and in the definitions embedded in the binary second,
+// and every other field of the body overrides the definition.
+// A body without the "pipeline" field is used as a complete transform.Config, as before.
+type PipelineResolver struct {
+	defs DefinitionStorer
+}
+
+// NewPipelineResolver creates a new PipelineResolver. defs may be nil to only use embedded definitions.
+func NewPipelineResolver(defs DefinitionStorer) *PipelineResolver {
+	return &PipelineResolver{
+		defs: defs,
+	}
+}
+
+// Resolve builds and validates the transform.Config described by the message body.
+// The fields of the body are kept as raw JSON until they are decoded into the config,
+// so that numbers such as a seed above 2^53 are not rounded through float64.
+func (r *PipelineResolver) Resolve(body []byte) (transform.Config, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return transform.Config{}, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+
+	merged := make(map[string]any, len(fields))
+	for k, v := range fields {
+		merged[k] = v
+	}
+	if name, ok := fields[pipelineKey]; ok {
+		var nameStr string
+		if err := json.Unmarshal(name, &nameStr); err != nil {
+			return transform.Config{}, fmt.Errorf("%w: pipeline must be a string", ErrInvalidPipeline)
+		}
+		def, err := r.definition(nameStr)
+		if err != nil {
+			return transform.Config{}, err
+		}
+		delete(merged, pipelineKey)
+		for k, v := range merged {
+			def[k] = v
+		}
+		merged = def
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return transform.Config{}, err
+	}
+	var cfg transform.Config
+	if err = json.Unmarshal(b, &cfg); err != nil {
+		return transform.Config{}, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	if err = cfg.Validate(); err != nil {
+		return transform.Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return cfg, nil
+}
+
+// definition loads the named pipeline definition as a field map.
+func (r *PipelineResolver) definition(name string) (map[string]any, error) {
+	raw, err := r.rawDefinition(name)
+	if err != nil {
+		return nil, err
+	}
+	// YAML is a superset of JSON, so both formats are accepted.
+	var def map[string]any
+	if err = yaml.Unmarshal(raw, &def); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPipeline, name, err)
+	}
+	if def == nil {
+		def = make(map[string]any)
+	}
+	return def, nil
+}
+
+func (r *PipelineResolver) rawDefinition(name string) ([]byte, error) {
+	if r.defs != nil {
+		raw, err := r.defs.PipelineDefinition(name)
+		switch {
+		case err == nil:
+			return raw, nil
+		case !errors.Is(err, store.ErrNotFound):
+			return nil, err
+		}
+	}
+
+	raw, err := embeddedPipelines.ReadFile("pipelines/" + name + ".yaml")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrPipelineNotFound, name)
+		}
+		return nil, err
+	}
+	return raw, nil
+}
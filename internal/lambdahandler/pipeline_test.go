@@ -0,0 +1,115 @@
+package lambdahandler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/opplieam/bb-transform/internal/store"
+	"github.com/opplieam/bb-transform/internal/transform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineResolverResolve(t *testing.T) {
+	type mockBehavior func(defs *MockDefinitionStorer)
+	tests := []struct {
+		name         string
+		body         string
+		mockBehavior mockBehavior
+		want         transform.Config
+		wantErr      error
+	}{
+		{
+			name:         "Legacy config",
+			body:         `{"version":"v1","shuffle":true,"train_ratio":60,"validate_ratio":20,"test_ratio":20}`,
+			mockBehavior: func(defs *MockDefinitionStorer) {},
+			want:         transform.Config{Version: "v1", Shuffle: true, TrainRatio: 60, ValidateRatio: 20, TestRatio: 20},
+		},
+		{
+			name: "Stored definition with overrides",
+			body: `{"pipeline":"small","version":"v2","test_ratio":30}`,
+			mockBehavior: func(defs *MockDefinitionStorer) {
+				defs.EXPECT().PipelineDefinition("small").Return([]byte("train_ratio: 50\ntest_ratio: 50\nmax_depth: 4\n"), nil)
+			},
+			want: transform.Config{Version: "v2", TrainRatio: 50, TestRatio: 30, MaxDepth: 4},
+		},
+		{
+			name: "Embedded definition",
+			body: `{"pipeline":"balanced","version":"v3"}`,
+			mockBehavior: func(defs *MockDefinitionStorer) {
+				defs.EXPECT().PipelineDefinition("balanced").Return(nil, store.ErrNotFound)
+			},
+			want: transform.Config{
+				Version:        "v3",
+				Shuffle:        true,
+				TrainRatio:     80,
+				ValidateRatio:  10,
+				TestRatio:      10,
+				MaxPerCategory: 500,
+				Stages: []string{
+					transform.StageFilter, transform.StageNormalize, transform.StageDedupe, transform.StageBalance,
+					transform.StageSample, transform.StageSplit, transform.StageEnrich, transform.StageWrite,
+				},
+			},
+		},
+		{
+			name:         "Seed above 2^53",
+			body:         `{"version":"v1","train_ratio":100,"seed":1760873370123456789}`,
+			mockBehavior: func(defs *MockDefinitionStorer) {},
+			want:         transform.Config{Version: "v1", TrainRatio: 100, Seed: 1760873370123456789},
+		},
+		{
+			name: "Seed above 2^53 overriding a definition",
+			body: `{"pipeline":"small","version":"v2","seed":1760873370123456789}`,
+			mockBehavior: func(defs *MockDefinitionStorer) {
+				defs.EXPECT().PipelineDefinition("small").Return([]byte("train_ratio: 100\nseed: 1760873370123456788\n"), nil)
+			},
+			want: transform.Config{Version: "v2", TrainRatio: 100, Seed: 1760873370123456789},
+		},
+		{
+			name: "Unknown pipeline",
+			body: `{"pipeline":"nope","version":"v1"}`,
+			mockBehavior: func(defs *MockDefinitionStorer) {
+				defs.EXPECT().PipelineDefinition("nope").Return(nil, store.ErrNotFound)
+			},
+			wantErr: ErrPipelineNotFound,
+		},
+		{
+			name: "Store error",
+			body: `{"pipeline":"small","version":"v1"}`,
+			mockBehavior: func(defs *MockDefinitionStorer) {
+				defs.EXPECT().PipelineDefinition("small").Return(nil, errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+		{
+			name:         "Invalid body",
+			body:         `not json`,
+			mockBehavior: func(defs *MockDefinitionStorer) {},
+			wantErr:      ErrUnmarshalConfig,
+		},
+		{
+			name:         "Invalid config",
+			body:         `{"version":"v1","stages":["magic"]}`,
+			mockBehavior: func(defs *MockDefinitionStorer) {},
+			wantErr:      ErrInvalidConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs := NewMockDefinitionStorer(t)
+			tt.mockBehavior(defs)
+
+			cfg, err := NewPipelineResolver(defs).Resolve([]byte(tt.body))
+			if tt.wantErr != nil {
+				if errors.Is(err, tt.wantErr) {
+					return
+				}
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
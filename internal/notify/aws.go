@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// SQSSender is the part of the SQS client used by Queue.
+type SQSSender interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// SNSPublisher is the part of the SNS client used by Topic.
+type SNSPublisher interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// Queue is a Notifier that sends every event as a JSON message to an SQS queue.
+type Queue struct {
+	client   SQSSender
+	queueURL string
+}
+
+// NewQueue creates a new Queue sending to queueURL.
+func NewQueue(client SQSSender, queueURL string) *Queue {
+	return &Queue{
+		client:   client,
+		queueURL: queueURL,
+	}
+}
+
+// Notify sends the event to the queue.
+func (q *Queue) Notify(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(q.queueURL),
+		MessageBody: aws.String(string(body)),
+	})
+	return err
+}
+
+// Topic is a Notifier that publishes every event as a JSON message to an SNS topic.
+type Topic struct {
+	client   SNSPublisher
+	topicARN string
+}
+
+// NewTopic creates a new Topic publishing to topicARN.
+func NewTopic(client SNSPublisher, topicARN string) *Topic {
+	return &Topic{
+		client:   client,
+		topicARN: topicARN,
+	}
+}
+
+// Notify publishes the event to the topic.
+func (t *Topic) Notify(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = t.client.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(t.topicARN),
+		Message:  aws.String(string(body)),
+	})
+	return err
+}
+
+// FromEnv builds a Notifier from ResultQueueURLEnv, ResultTopicARNEnv and ResultWebhookURLEnv.
+// Every target that is set receives all events; Nop is returned when none is set.
+// AWS clients use the default credential chain, so no AWS configuration is loaded unless a queue or topic is set.
+func FromEnv(ctx context.Context) (Notifier, error) {
+	queueURL := os.Getenv(ResultQueueURLEnv)
+	topicARN := os.Getenv(ResultTopicARNEnv)
+	webhookURL := os.Getenv(ResultWebhookURLEnv)
+
+	var m Multi
+	if queueURL != "" || topicARN != "" {
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if queueURL != "" {
+			m = append(m, NewQueue(sqs.NewFromConfig(cfg), queueURL))
+		}
+		if topicARN != "" {
+			m = append(m, NewTopic(sns.NewFromConfig(cfg), topicARN))
+		}
+	}
+	if webhookURL != "" {
+		m = append(m, NewWebhook(webhookURL, nil))
+	}
+
+	switch len(m) {
+	case 0:
+		return Nop{}, nil
+	case 1:
+		return m[0], nil
+	default:
+		return m, nil
+	}
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSQS struct {
+	inputs []*sqs.SendMessageInput
+}
+
+func (f *fakeSQS) SendMessage(_ context.Context, in *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.inputs = append(f.inputs, in)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+type fakeSNS struct {
+	inputs []*sns.PublishInput
+}
+
+func (f *fakeSNS) Publish(_ context.Context, in *sns.PublishInput, _ ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	f.inputs = append(f.inputs, in)
+	return &sns.PublishOutput{}, nil
+}
+
+func TestQueue(t *testing.T) {
+	client := &fakeSQS{}
+	err := NewQueue(client, "https://sqs/results").Notify(context.Background(), Event{MessageID: "1", Status: StatusFailed, Error: "boom"})
+	require.NoError(t, err)
+	require.Len(t, client.inputs, 1)
+	assert.Equal(t, "https://sqs/results", aws.ToString(client.inputs[0].QueueUrl))
+	assert.Contains(t, aws.ToString(client.inputs[0].MessageBody), `"error":"boom"`)
+}
+
+func TestTopic(t *testing.T) {
+	client := &fakeSNS{}
+	err := NewTopic(client, "arn:aws:sns:us-east-2:1:results").Notify(context.Background(), Event{MessageID: "1", Status: StatusSucceeded})
+	require.NoError(t, err)
+	require.Len(t, client.inputs, 1)
+	assert.Equal(t, "arn:aws:sns:us-east-2:1:results", aws.ToString(client.inputs[0].TopicArn))
+	assert.Contains(t, aws.ToString(client.inputs[0].Message), `"status":"succeeded"`)
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory is a Notifier that keeps every event in memory, as a stand-in for tests and local development.
+type Memory struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// Notify appends the event.
+func (m *Memory) Notify(_ context.Context, ev Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, ev)
+	return nil
+}
+
+// Events returns a copy of the events received so far.
+func (m *Memory) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Event(nil), m.events...)
+}
@@ -0,0 +1,63 @@
+// Package notify publishes job completion events so that whoever requested a dataset job learns about
+// its outcome without polling the database. Events can be sent to an SQS queue, an SNS topic or an HTTP webhook
+// through the Notifier interface, and collected in memory for tests and local development.
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Event statuses. StatusContinued marks a generation that ran out of time and was re-enqueued
+// to resume from its checkpoint; another event follows when the continuation finishes.
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusContinued = "continued"
+)
+
+// Environment variables read by FromEnv. Every one that is set adds a notifier.
+const (
+	ResultQueueURLEnv   = "RESULT_QUEUE_URL"
+	ResultTopicARNEnv   = "RESULT_TOPIC_ARN"
+	ResultWebhookURLEnv = "RESULT_WEBHOOK_URL"
+)
+
+// Event describes the outcome of a single job message.
+// Counts holds the number of dataset rows per split label for generate jobs.
+type Event struct {
+	MessageID  string         `json:"message_id"`
+	Type       string         `json:"type"`
+	Version    string         `json:"version,omitempty"`
+	Status     string         `json:"status"`
+	Counts     map[string]int `json:"counts,omitempty"`
+	DurationMS int64          `json:"duration_ms"`
+	Error      string         `json:"error,omitempty"`
+	FinishedAt time.Time      `json:"finished_at"`
+}
+
+// Notifier publishes job completion events.
+type Notifier interface {
+	Notify(ctx context.Context, ev Event) error
+}
+
+// Nop is a Notifier that drops every event. It is used when no notification target is configured.
+type Nop struct{}
+
+// Notify does nothing.
+func (Nop) Notify(context.Context, Event) error { return nil }
+
+// Multi sends every event to all of its notifiers and joins their errors.
+type Multi []Notifier
+
+// Notify sends the event to every notifier, even when one of them fails.
+func (m Multi) Notify(ctx context.Context, ev Event) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
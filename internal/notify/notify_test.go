@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(context.Context, Event) error { return errors.New("notify error") }
+
+func TestMulti(t *testing.T) {
+	mem := &Memory{}
+	m := Multi{failingNotifier{}, mem}
+
+	err := m.Notify(context.Background(), Event{MessageID: "1", Status: StatusSucceeded})
+	assert.EqualError(t, err, "notify error")
+	assert.Equal(t, []Event{{MessageID: "1", Status: StatusSucceeded}}, mem.Events())
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(ResultQueueURLEnv, "")
+	t.Setenv(ResultTopicARNEnv, "")
+	t.Setenv(ResultWebhookURLEnv, "")
+	n, err := FromEnv(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, Nop{}, n)
+
+	t.Setenv(ResultWebhookURLEnv, "http://localhost:8080/hook")
+	n, err = FromEnv(context.Background())
+	assert.NoError(t, err)
+	assert.IsType(t, &Webhook{}, n)
+}
@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrWebhookStatus = errors.New("webhook responded with an unexpected status")
+)
+
+const webhookTimeout = 5 * time.Second
+
+// Webhook is a Notifier that POSTs every event as JSON to an HTTP endpoint.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates a new Webhook posting to url. A nil client uses one with a short timeout.
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+	return &Webhook{
+		url:    url,
+		client: client,
+	}
+}
+
+// Notify posts the event and returns ErrWebhookStatus unless the endpoint answers with a 2xx status.
+func (w *Webhook) Notify(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %d", ErrWebhookStatus, resp.StatusCode)
+	}
+	return nil
+}
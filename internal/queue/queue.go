@@ -0,0 +1,88 @@
+// Package queue sends job messages to the transform queue, so that a job can enqueue follow-up jobs
+// (e.g. the continuation of a generation that ran out of time). Messages can be sent to SQS or kept in memory
+// for tests and local development.
+package queue
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// QueueURLEnv is the environment variable holding the URL of the transform queue.
+const QueueURLEnv = "TRANSFORM_QUEUE_URL"
+
+// Sender sends a message body to the transform queue.
+type Sender interface {
+	Send(ctx context.Context, body []byte) error
+}
+
+// SQSClient is the part of the SQS client used by SQS and SQSSource.
+type SQSClient interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+}
+
+// SQS is a Sender backed by an SQS queue.
+type SQS struct {
+	client   SQSClient
+	queueURL string
+}
+
+// NewSQS creates a new SQS sender for queueURL.
+func NewSQS(client SQSClient, queueURL string) *SQS {
+	return &SQS{
+		client:   client,
+		queueURL: queueURL,
+	}
+}
+
+// Send sends the message body to the queue.
+func (q *SQS) Send(ctx context.Context, body []byte) error {
+	_, err := q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(q.queueURL),
+		MessageBody: aws.String(string(body)),
+	})
+	return err
+}
+
+// Memory is a Sender that keeps the messages in memory.
+type Memory struct {
+	mu       sync.Mutex
+	messages [][]byte
+}
+
+// Send appends the message body.
+func (m *Memory) Send(_ context.Context, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, body)
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far.
+func (m *Memory) Messages() [][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]byte(nil), m.messages...)
+}
+
+// FromEnv builds an SQS Sender for the queue named by QueueURLEnv, using the default AWS credential chain.
+// It returns nil when the variable is not set.
+func FromEnv(ctx context.Context) (Sender, error) {
+	queueURL := os.Getenv(QueueURLEnv)
+	if queueURL == "" {
+		return nil, nil //nolint:nilnil // No queue configured
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewSQS(sqs.NewFromConfig(cfg), queueURL), nil
+}
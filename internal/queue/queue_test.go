@@ -0,0 +1,65 @@
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSQS struct {
+	inputs   []*sqs.SendMessageInput
+	messages []types.Message
+	deleted  []string
+	released map[string]int32
+}
+
+func (f *fakeSQS) SendMessage(_ context.Context, in *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.inputs = append(f.inputs, in)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ReceiveMessage(_ context.Context, _ *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	out := &sqs.ReceiveMessageOutput{Messages: f.messages}
+	f.messages = nil
+	return out, nil
+}
+
+func (f *fakeSQS) DeleteMessage(_ context.Context, in *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.deleted = append(f.deleted, aws.ToString(in.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(_ context.Context, in *sqs.ChangeMessageVisibilityInput, _ ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	if f.released == nil {
+		f.released = make(map[string]int32)
+	}
+	f.released[aws.ToString(in.ReceiptHandle)] = in.VisibilityTimeout
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func TestSQS(t *testing.T) {
+	client := &fakeSQS{}
+	require.NoError(t, NewSQS(client, "https://sqs/transform").Send(context.Background(), []byte(`{"type":"generate"}`)))
+	require.Len(t, client.inputs, 1)
+	assert.Equal(t, "https://sqs/transform", aws.ToString(client.inputs[0].QueueUrl))
+	assert.Equal(t, `{"type":"generate"}`, aws.ToString(client.inputs[0].MessageBody))
+}
+
+func TestMemory(t *testing.T) {
+	m := &Memory{}
+	require.NoError(t, m.Send(context.Background(), []byte("a")))
+	require.NoError(t, m.Send(context.Background(), []byte("b")))
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, m.Messages())
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(QueueURLEnv, "")
+	s, err := FromEnv(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, s)
+}
@@ -0,0 +1,186 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Default polling settings of the sources.
+const (
+	DefaultWaitTime          = 20 * time.Second
+	DefaultVisibilityTimeout = 5 * time.Minute
+	DefaultPollInterval      = time.Second
+)
+
+// Subdirectories of a DirSource that processed and failed messages are moved to.
+const (
+	DirDone   = "done"
+	DirFailed = "failed"
+)
+
+var (
+	ErrInvalidDir = errors.New("invalid queue directory")
+)
+
+// SQSSource is a Source that long-polls an SQS queue, or any SQS-compatible endpoint such as ElasticMQ or LocalStack.
+// Received messages are hidden for VisibilityTimeout, which must be longer than a message takes to process.
+type SQSSource struct {
+	client            SQSClient
+	queueURL          string
+	WaitTime          time.Duration
+	VisibilityTimeout time.Duration
+}
+
+// NewSQSSource creates a new SQSSource for queueURL with DefaultWaitTime and DefaultVisibilityTimeout.
+func NewSQSSource(client SQSClient, queueURL string) *SQSSource {
+	return &SQSSource{
+		client:            client,
+		queueURL:          queueURL,
+		WaitTime:          DefaultWaitTime,
+		VisibilityTimeout: DefaultVisibilityTimeout,
+	}
+}
+
+// Receive waits up to WaitTime for a message.
+func (s *SQSSource) Receive(ctx context.Context) ([]Message, error) {
+	out, err := s.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(s.queueURL),
+		MaxNumberOfMessages:         1,
+		WaitTimeSeconds:             int32(s.WaitTime.Seconds()),
+		VisibilityTimeout:           int32(s.VisibilityTimeout.Seconds()),
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameApproximateReceiveCount},
+	})
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]Message, 0, len(out.Messages))
+	for _, m := range out.Messages {
+		count, _ := strconv.Atoi(m.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+		msgs = append(msgs, Message{
+			ID:            aws.ToString(m.MessageId),
+			ReceiptHandle: aws.ToString(m.ReceiptHandle),
+			Body:          aws.ToString(m.Body),
+			ReceiveCount:  count,
+		})
+	}
+	return msgs, nil
+}
+
+// Delete deletes the message from the queue.
+func (s *SQSSource) Delete(ctx context.Context, m Message) error {
+	_, err := s.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(s.queueURL),
+		ReceiptHandle: aws.String(m.ReceiptHandle),
+	})
+	return err
+}
+
+// Release makes the message visible again after delay instead of waiting for its visibility timeout,
+// so the redrive policy of the queue applies as it does for the Lambda function.
+func (s *SQSSource) Release(ctx context.Context, m Message, delay time.Duration) error {
+	_, err := s.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(s.queueURL),
+		ReceiptHandle:     aws.String(m.ReceiptHandle),
+		VisibilityTimeout: int32(delay.Seconds()),
+	})
+	return err
+}
+
+// DirSource is a Source that reads messages from the *.json files of a directory, in file name order.
+// Every file holds one message body. Processed files are moved to the DirDone subdirectory
+// and failed ones to DirFailed, from where they can be moved back to be retried.
+// The message ID, which keys the job ledger, is the file name together with its modification time,
+// so a new file reusing the name of a processed one is a new message, while a file moved back from DirFailed is not.
+// When the directory is empty, Receive polls it every PollInterval.
+type DirSource struct {
+	dir          string
+	PollInterval time.Duration
+}
+
+// NewDirSource creates a new DirSource for dir, creating its DirDone and DirFailed subdirectories.
+func NewDirSource(dir string) (*DirSource, error) {
+	for _, sub := range []string{DirDone, DirFailed} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o750); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidDir, err)
+		}
+	}
+	return &DirSource{
+		dir:          dir,
+		PollInterval: DefaultPollInterval,
+	}, nil
+}
+
+// Receive returns the first message file, waiting for one to appear.
+func (s *DirSource) Receive(ctx context.Context) ([]Message, error) {
+	for {
+		paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) > 0 {
+			slices.Sort(paths)
+			info, sErr := os.Stat(paths[0])
+			if sErr != nil {
+				return nil, sErr
+			}
+			body, rErr := os.ReadFile(paths[0])
+			if rErr != nil {
+				return nil, rErr
+			}
+			name := filepath.Base(paths[0])
+			id := fmt.Sprintf("%s@%d", name, info.ModTime().UnixNano())
+			return []Message{{ID: id, ReceiptHandle: name, Body: string(body), ReceiveCount: 1}}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.PollInterval):
+		}
+	}
+}
+
+// Delete moves the message file to DirDone.
+func (s *DirSource) Delete(_ context.Context, m Message) error {
+	return os.Rename(filepath.Join(s.dir, m.ReceiptHandle), filepath.Join(s.dir, DirDone, m.ReceiptHandle))
+}
+
+// Release moves the message file to DirFailed. The delay is not used.
+func (s *DirSource) Release(_ context.Context, m Message, _ time.Duration) error {
+	return os.Rename(filepath.Join(s.dir, m.ReceiptHandle), filepath.Join(s.dir, DirFailed, m.ReceiptHandle))
+}
+
+// Dir is a Sender that writes every message to a new file in a directory read by a DirSource.
+type Dir struct {
+	dir string
+	seq atomic.Int64
+}
+
+// NewDir creates a new Dir sender for dir.
+func NewDir(dir string) *Dir {
+	return &Dir{
+		dir: dir,
+	}
+}
+
+// Send writes the message body to a file named after the current time, so it sorts after the existing ones.
+func (d *Dir) Send(_ context.Context, body []byte) error {
+	name := fmt.Sprintf("%d-%04d.json", time.Now().UnixNano(), d.seq.Add(1))
+	tmp := filepath.Join(d.dir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, body, 0o600); err != nil {
+		return err
+	}
+	// Rename so that a DirSource never reads a partially written file.
+	return os.Rename(tmp, filepath.Join(d.dir, name))
+}
@@ -0,0 +1,101 @@
+package queue
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Message is a message received from a Source.
+// ReceiptHandle identifies the delivery and is used to delete or release the message.
+type Message struct {
+	ID            string
+	ReceiptHandle string
+	Body          string
+	ReceiveCount  int
+}
+
+// Source is a queue the Worker consumes from.
+// Delete removes a processed message; Release makes a failed message available again after delay.
+// Receive waits for messages and may return none.
+type Source interface {
+	Receive(ctx context.Context) ([]Message, error)
+	Delete(ctx context.Context, m Message) error
+	Release(ctx context.Context, m Message, delay time.Duration) error
+}
+
+// HandlerFunc processes an SQS event, e.g. lambdahandler.Handler.HandleSQSEvent.
+type HandlerFunc func(ctx context.Context, ev events.SQSEvent) error
+
+// Worker runs the Lambda handler locally: it consumes messages from a Source one by one and passes each
+// to the handler as a single-record SQS event, the same way the Lambda event source mapping does.
+// Processed messages are deleted; failed messages are released to be received again after RetryDelay.
+// Timeout, when set, is the deadline of each invocation, like the Lambda timeout.
+type Worker struct {
+	log        *slog.Logger
+	source     Source
+	handle     HandlerFunc
+	Timeout    time.Duration
+	RetryDelay time.Duration
+}
+
+// NewWorker creates a new Worker consuming from src.
+func NewWorker(l *slog.Logger, src Source, handle HandlerFunc) *Worker {
+	return &Worker{
+		log:    l.With("component", "worker"),
+		source: src,
+		handle: handle,
+	}
+}
+
+// Run consumes messages until ctx is canceled, which is not reported as an error.
+// It returns an error if the Source fails to receive, delete or release a message.
+func (w *Worker) Run(ctx context.Context) error {
+	w.log.Info("worker started")
+	for {
+		msgs, err := w.source.Receive(ctx)
+		if ctx.Err() != nil {
+			w.log.Info("worker stopped")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if err = w.process(ctx, m); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *Worker) process(ctx context.Context, m Message) error {
+	invokeCtx := ctx
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		invokeCtx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+
+	ev := events.SQSEvent{Records: []events.SQSMessage{{
+		MessageId:     m.ID,
+		ReceiptHandle: m.ReceiptHandle,
+		Body:          m.Body,
+		Attributes:    map[string]string{"ApproximateReceiveCount": strconv.Itoa(m.ReceiveCount)},
+		EventSource:   "aws:sqs",
+	}}}
+	if err := w.handle(invokeCtx, ev); err != nil {
+		w.log.Warn("message failed, releasing it", "message_id", m.ID, "receive_count", m.ReceiveCount,
+			"retry_delay", w.RetryDelay, "error", err,
+		)
+		return w.source.Release(context.WithoutCancel(ctx), m, w.RetryDelay)
+	}
+	if err := w.source.Delete(context.WithoutCancel(ctx), m); err != nil {
+		return err
+	}
+	w.log.Info("message processed", "message_id", m.ID)
+	return nil
+}
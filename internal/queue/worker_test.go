@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+// stopAfter returns a handler that records the bodies it receives, fails the ones in fail
+// and cancels the worker once n messages have been handled.
+func stopAfter(n int, cancel context.CancelFunc, fail string, bodies *[]string) HandlerFunc {
+	return func(ctx context.Context, ev events.SQSEvent) error {
+		*bodies = append(*bodies, ev.Records[0].Body)
+		if len(*bodies) == n {
+			cancel()
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			return errors.New("no deadline")
+		}
+		if ev.Records[0].Body == fail {
+			return errors.New("job failed")
+		}
+		return nil
+	}
+}
+
+func TestWorkerDir(t *testing.T) {
+	dir := t.TempDir()
+	src, err := NewDirSource(dir)
+	require.NoError(t, err)
+	src.PollInterval = time.Millisecond
+
+	sender := NewDir(dir)
+	require.NoError(t, sender.Send(context.Background(), []byte(`{"version":"v1"}`)))
+	require.NoError(t, sender.Send(context.Background(), []byte(`{"version":"v2"}`)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var bodies []string
+	w := NewWorker(testLogger(), src, stopAfter(2, cancel, `{"version":"v2"}`, &bodies))
+	w.Timeout = time.Minute
+	require.NoError(t, w.Run(ctx))
+
+	assert.Equal(t, []string{`{"version":"v1"}`, `{"version":"v2"}`}, bodies)
+	done, _ := filepath.Glob(filepath.Join(dir, DirDone, "*.json"))
+	failed, _ := filepath.Glob(filepath.Join(dir, DirFailed, "*.json"))
+	pending, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	assert.Len(t, done, 1)
+	assert.Len(t, failed, 1)
+	assert.Empty(t, pending)
+}
+
+func TestDirSourceMessageID(t *testing.T) {
+	dir := t.TempDir()
+	src, err := NewDirSource(dir)
+	require.NoError(t, err)
+	path := filepath.Join(dir, "001.json")
+	receive := func(mtime time.Time) Message {
+		require.NoError(t, os.WriteFile(path, []byte(`{"version":"v1"}`), 0o600))
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+		msgs, err := src.Receive(context.Background())
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		return msgs[0]
+	}
+
+	first := receive(time.Unix(1000, 0))
+	assert.Equal(t, "001.json", first.ReceiptHandle)
+	require.NoError(t, src.Release(context.Background(), first, 0))
+
+	// A failed file moved back keeps its ID, a new file with the same name gets another one.
+	require.NoError(t, os.Rename(filepath.Join(dir, DirFailed, "001.json"), path))
+	msgs, err := src.Receive(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, msgs[0].ID)
+	require.NoError(t, src.Delete(context.Background(), msgs[0]))
+
+	assert.NotEqual(t, first.ID, receive(time.Unix(2000, 0)).ID)
+}
+
+func TestWorkerSQS(t *testing.T) {
+	client := &fakeSQS{messages: []types.Message{
+		{MessageId: aws.String("1"), ReceiptHandle: aws.String("r-1"), Body: aws.String("ok")},
+		{MessageId: aws.String("2"), ReceiptHandle: aws.String("r-2"), Body: aws.String("bad")},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var bodies []string
+	w := NewWorker(testLogger(), NewSQSSource(client, "http://localhost:9324/queue/transform"), stopAfter(2, cancel, "bad", &bodies))
+	w.Timeout = time.Minute
+	w.RetryDelay = 30 * time.Second
+	require.NoError(t, w.Run(ctx))
+
+	assert.Equal(t, []string{"ok", "bad"}, bodies)
+	assert.Equal(t, []string{"r-1"}, client.deleted)
+	assert.Equal(t, map[string]int32{"r-2": 30}, client.released)
+}
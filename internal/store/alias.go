@@ -0,0 +1,108 @@
+package store
+
+import (
+	"errors"
+
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/opplieam/bb-transform/.jetgen/postgres/public/table"
+
+	"github.com/opplieam/bb-transform/internal/transform"
+)
+
+// MoveAliases points the aliases at the version in the 'dataset_alias' table, creating the ones that do not exist.
+// All aliases are upserted by a single statement, so either every alias moves or none does.
+// It returns an error if the upsert fails.
+func (c *CategoryStore) MoveAliases(version string, names []string) error {
+	stmt := DatasetAlias.INSERT(
+		DatasetAlias.Name, DatasetAlias.Version,
+	)
+	for _, name := range names {
+		stmt = stmt.VALUES(name, version)
+	}
+	stmt = stmt.ON_CONFLICT(
+		DatasetAlias.Name,
+	).DO_UPDATE(
+		SET(
+			DatasetAlias.Version.SET(DatasetAlias.EXCLUDED.Version),
+			DatasetAlias.UpdatedAt.SET(LOCALTIMESTAMP()),
+		),
+	)
+	if _, err := stmt.Exec(c.db); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Alias retrieves an alias from the 'dataset_alias' table.
+// It returns transform.ErrAliasNotFound if the alias does not exist, or an error if the query fails.
+func (c *CategoryStore) Alias(name string) (transform.Alias, error) {
+	stmt := SELECT(
+		DatasetAlias.AllColumns,
+	).FROM(
+		DatasetAlias,
+	).WHERE(
+		DatasetAlias.Name.EQ(String(name)),
+	)
+
+	var dest model.DatasetAlias
+	if err := stmt.Query(c.db, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return transform.Alias{}, transform.ErrAliasNotFound
+		}
+		return transform.Alias{}, err
+	}
+	return toAlias(dest), nil
+}
+
+// ListAliases retrieves every alias from the 'dataset_alias' table, ordered by name.
+// It returns an error if the query fails.
+func (c *CategoryStore) ListAliases() ([]transform.Alias, error) {
+	stmt := SELECT(
+		DatasetAlias.AllColumns,
+	).FROM(
+		DatasetAlias,
+	).ORDER_BY(
+		DatasetAlias.Name.ASC(),
+	)
+
+	var dest []model.DatasetAlias
+	if err := stmt.Query(c.db, &dest); err != nil {
+		return nil, err
+	}
+	aliases := make([]transform.Alias, 0, len(dest))
+	for _, v := range dest {
+		aliases = append(aliases, toAlias(v))
+	}
+	return aliases, nil
+}
+
+// DeleteAlias removes an alias from the 'dataset_alias' table.
+// It returns transform.ErrAliasNotFound if the alias does not exist, or an error if the deletion fails.
+func (c *CategoryStore) DeleteAlias(name string) error {
+	stmt := DatasetAlias.DELETE().WHERE(DatasetAlias.Name.EQ(String(name)))
+	res, err := stmt.Exec(c.db)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return transform.ErrAliasNotFound
+	}
+	return nil
+}
+
+func toAlias(a model.DatasetAlias) transform.Alias {
+	return transform.Alias{
+		Name:      a.Name,
+		Version:   a.Version,
+		UpdatedAt: a.UpdatedAt,
+	}
+}
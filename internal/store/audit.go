@@ -0,0 +1,114 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/go-jet/jet/v2/postgres"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/opplieam/bb-transform/.jetgen/postgres/public/table"
+
+	"github.com/opplieam/bb-transform/internal/audit"
+)
+
+// AuditStore provides methods for the append-only 'audit_log' table. Entries are only ever inserted and queried.
+type AuditStore struct {
+	db *sql.DB
+}
+
+// NewAuditStore creates a new instance of AuditStore.
+// It takes a *sql.DB connection as input and returns a pointer to an AuditStore.
+func NewAuditStore(db *sql.DB) *AuditStore {
+	return &AuditStore{
+		db: db,
+	}
+}
+
+// Record inserts the entry into the 'audit_log' table. The ID of the entry is assigned by the database.
+// It returns an error if the insert fails.
+func (a *AuditStore) Record(e audit.Entry) error {
+	var config, errMsg Expression = NULL, NULL
+	if len(e.Config) > 0 {
+		config = String(string(e.Config))
+	}
+	if e.Error != "" {
+		errMsg = String(e.Error)
+	}
+	stmt := AuditLog.INSERT(
+		AuditLog.Operation, AuditLog.Version, AuditLog.Actor, AuditLog.MessageID, AuditLog.Source, AuditLog.Config,
+		AuditLog.Outcome, AuditLog.Error, AuditLog.StartedAt, AuditLog.FinishedAt, AuditLog.DurationMs,
+	).VALUES(
+		e.Operation, e.Version, e.Actor, e.MessageID, e.Source, config,
+		e.Outcome, errMsg, e.StartedAt, e.FinishedAt, e.DurationMS,
+	)
+	if _, err := stmt.Exec(a.db); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Entries retrieves the entries selected by the query from the 'audit_log' table, newest first.
+// It returns an error if the query fails.
+func (a *AuditStore) Entries(q audit.Query) ([]audit.Entry, error) {
+	condition := Bool(true)
+	if q.Version != "" {
+		condition = condition.AND(AuditLog.Version.EQ(String(q.Version)))
+	}
+	if q.Operation != "" {
+		condition = condition.AND(AuditLog.Operation.EQ(String(q.Operation)))
+	}
+	if q.Actor != "" {
+		condition = condition.AND(AuditLog.Actor.EQ(String(q.Actor)))
+	}
+	if !q.Since.IsZero() {
+		condition = condition.AND(AuditLog.StartedAt.GT_EQ(TimestampT(q.Since)))
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = audit.DefaultLimit
+	}
+	stmt := SELECT(
+		AuditLog.AllColumns,
+	).FROM(
+		AuditLog,
+	).WHERE(
+		condition,
+	).ORDER_BY(
+		AuditLog.StartedAt.DESC(), AuditLog.ID.DESC(),
+	).LIMIT(int64(limit))
+
+	var dest []model.AuditLog
+	if err := stmt.Query(a.db, &dest); err != nil {
+		return nil, err
+	}
+	entries := make([]audit.Entry, 0, len(dest))
+	for _, v := range dest {
+		entries = append(entries, toAuditEntry(v))
+	}
+	return entries, nil
+}
+
+func toAuditEntry(v model.AuditLog) audit.Entry {
+	e := audit.Entry{
+		ID:         v.ID,
+		Operation:  v.Operation,
+		Version:    v.Version,
+		Actor:      v.Actor,
+		MessageID:  v.MessageID,
+		Source:     v.Source,
+		Outcome:    v.Outcome,
+		StartedAt:  v.StartedAt,
+		FinishedAt: v.FinishedAt,
+		DurationMS: v.DurationMs,
+	}
+	if v.Config != nil {
+		e.Config = json.RawMessage(*v.Config)
+	}
+	if v.Error != nil {
+		e.Error = *v.Error
+	}
+	return e
+}
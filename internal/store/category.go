@@ -3,7 +3,9 @@
 package store
 
 import (
+	"cmp"
 	"database/sql"
+	"slices"
 	//nolint:revive,stylecheck // simulate SQL
 	. "github.com/go-jet/jet/v2/postgres"
 
@@ -29,38 +31,39 @@ func NewCategoryStore(db *sql.DB) *CategoryStore {
 }
 
 // AllCategoryResult represents the result structure for a query that fetches all categories,
-// including their ID, name, whether they have children, and their hierarchical path.
+// including their ID, name, parent ID, whether they have children, and their hierarchical path.
 // The structure is designed to be used with a recursive CTE query to extract hierarchical data.
 type AllCategoryResult struct {
 	ID       int32  `sql:"primary_key" alias:"category.id" `
 	Name     string `alias:"category.name" `
 	HasChild bool   `alias:"category.has_child" `
+	ParentID *int32 `alias:"category.parent_id" `
 	Path     string
 }
 
-// OriginalCategory retrieves the original category structure from the database.
-// It constructs a recursive Common Table Expression (CTE) to traverse the category hierarchy,
+// categoryPath runs a recursive Common Table Expression (CTE) that traverses the category hierarchy,
 // starting from categories with no parent (root categories) and recursively joining
-// child categories until it reaches the deepest level. The function then filters
-// the result to include only the deepest categories (categories without children),
-// which represent the end of each branch in the hierarchy. The result is a map
-// where the key is the category ID and the value is a struct containing the category's
-// name and its full hierarchical path, represented as a string concatenated with " > ".
-// The function returns an error if any issues occur during the database query.
-func (c *CategoryStore) OriginalCategory() (transform.Category, error) {
+// child categories until it reaches the deepest level. Each row carries the category's
+// full hierarchical path, represented as a string concatenated with " > ".
+// The optional filter receives the CTE so callers can restrict the final result.
+func (c *CategoryStore) categoryPath(filter func(cr CommonTableExpression) BoolExpression) ([]AllCategoryResult, error) {
 	cr := CTE("CategoryRecursive")
 	pathCol := StringColumn("AllCategoryResult.Path").From(cr)
+	condition := Bool(true)
+	if filter != nil {
+		condition = filter(cr)
+	}
 	stmt := WITH_RECURSIVE(
 		cr.AS(
 			SELECT(
-				Category.ID, Category.Name, Category.HasChild, CAST(Category.Name).AS_TEXT().AS(pathCol.Name()),
+				Category.ID, Category.Name, Category.HasChild, Category.ParentID, CAST(Category.Name).AS_TEXT().AS(pathCol.Name()),
 			).FROM(
 				Category,
 			).WHERE(
 				Category.ParentID.IS_NULL(),
 			).UNION(
 				SELECT(
-					Category.ID, Category.Name, Category.HasChild, CAST(pathCol.CONCAT(String(" > ")).CONCAT(Category.Name)).AS_TEXT(),
+					Category.ID, Category.Name, Category.HasChild, Category.ParentID, CAST(pathCol.CONCAT(String(" > ")).CONCAT(Category.Name)).AS_TEXT(),
 				).FROM(
 					Category.
 						INNER_JOIN(cr, Category.ParentID.EQ(Category.ID.From(cr))),
@@ -73,13 +76,29 @@ func (c *CategoryStore) OriginalCategory() (transform.Category, error) {
 		).FROM(
 			cr,
 		).WHERE(
-			Category.HasChild.From(cr).IS_FALSE(),
+			condition,
 		),
 	)
 	var dest []AllCategoryResult
 	if err := stmt.Query(c.db, &dest); err != nil {
 		return nil, err
 	}
+	return dest, nil
+}
+
+// OriginalCategory retrieves the original category structure from the database.
+// It walks the category hierarchy and then filters the result to include only the deepest
+// categories (categories without children), which represent the end of each branch in the hierarchy.
+// The result is a map where the key is the category ID and the value is a struct containing
+// the category's name and its full hierarchical path.
+// The function returns an error if any issues occur during the database query.
+func (c *CategoryStore) OriginalCategory() (transform.Category, error) {
+	dest, err := c.categoryPath(func(cr CommonTableExpression) BoolExpression {
+		return Category.HasChild.From(cr).IS_FALSE()
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	catResult := make(transform.Category)
 	for _, v := range dest {
@@ -91,15 +110,44 @@ func (c *CategoryStore) OriginalCategory() (transform.Category, error) {
 	return catResult, nil
 }
 
+// CategoryTree retrieves every node of the category hierarchy, including intermediate categories,
+// together with its parent ID and full hierarchical path. The result is ordered by category ID.
+// It is used to snapshot the label space alongside a dataset version.
+func (c *CategoryStore) CategoryTree() ([]transform.CategoryNode, error) {
+	dest, err := c.categoryPath(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make([]transform.CategoryNode, 0, len(dest))
+	for _, v := range dest {
+		tree = append(tree, transform.CategoryNode{
+			ID:       v.ID,
+			Name:     v.Name,
+			ParentID: v.ParentID,
+			HasChild: v.HasChild,
+			Path:     v.Path,
+		})
+	}
+	slices.SortFunc(tree, func(a, b transform.CategoryNode) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return tree, nil
+}
+
 // MatchedCategory retrieves all matched categories from the 'match_category' table where 'match_id' is not null.
+// The L1 allow and deny lists and the AfterID and UpToID of the filter are pushed down into the query,
+// and the rows are ordered by ID so that seeded shuffles and splits see them in the same order on every run.
 // It returns a slice of model.MatchCategory representing the matched categories or an error if the query fails.
-func (c *CategoryStore) MatchedCategory() ([]model.MatchCategory, error) {
+func (c *CategoryStore) MatchedCategory(filter transform.MatchFilter) ([]model.MatchCategory, error) {
 	stmt := SELECT(
 		MatchCategory.AllColumns,
 	).FROM(
 		MatchCategory,
 	).WHERE(
-		MatchCategory.MatchID.IS_NOT_NULL(),
+		matchCondition(filter),
+	).ORDER_BY(
+		MatchCategory.ID.ASC(),
 	)
 
 	var dest []model.MatchCategory
@@ -109,6 +157,40 @@ func (c *CategoryStore) MatchedCategory() ([]model.MatchCategory, error) {
 	return dest, nil
 }
 
+// matchCondition selects the matched 'match_category' rows kept by the filter.
+func matchCondition(filter transform.MatchFilter) BoolExpression {
+	condition := MatchCategory.MatchID.IS_NOT_NULL()
+	if len(filter.AllowL1) > 0 {
+		condition = condition.AND(MatchCategory.L1.IN(stringExpressions(filter.AllowL1)...))
+	}
+	if len(filter.DenyL1) > 0 {
+		condition = condition.AND(MatchCategory.L1.NOT_IN(stringExpressions(filter.DenyL1)...))
+	}
+	if filter.AfterID > 0 {
+		condition = condition.AND(MatchCategory.ID.GT(Int32(filter.AfterID)))
+	}
+	if filter.UpToID > 0 {
+		condition = condition.AND(MatchCategory.ID.LT_EQ(Int32(filter.UpToID)))
+	}
+	return condition
+}
+
+func stringExpressions(values []string) []Expression {
+	exps := make([]Expression, 0, len(values))
+	for _, v := range values {
+		exps = append(exps, String(v))
+	}
+	return exps
+}
+
+func int32Expressions(values []int32) []Expression {
+	exps := make([]Expression, 0, len(values))
+	for _, v := range values {
+		exps = append(exps, Int32(v))
+	}
+	return exps
+}
+
 // CleanUp removes all category datasets from the 'category_dataset' table that match a specific version.
 // It takes a version string as input and returns an error if the deletion fails.
 func (c *CategoryStore) CleanUp(version string) error {
@@ -120,10 +202,34 @@ func (c *CategoryStore) CleanUp(version string) error {
 	return nil
 }
 
+// deleteBatchSize is the number of match_category IDs deleted at once by CleanUpRows,
+// which keeps every statement well below the bind parameter limit of Postgres.
+const deleteBatchSize = 1000
+
+// CleanUpRows removes the category datasets of a version in the 'category_dataset' table that were generated
+// from the given matched categories, e.g. by a failed continuation of a checkpointed run.
+// It returns an error if the deletion fails.
+func (c *CategoryStore) CleanUpRows(version string, matchCategoryIDs []int32) error {
+	for start := 0; start < len(matchCategoryIDs); start += deleteBatchSize {
+		ids := matchCategoryIDs[start:min(start+deleteBatchSize, len(matchCategoryIDs))]
+		stmt := CategoryDataset.DELETE().WHERE(
+			CategoryDataset.Version.EQ(String(version)).
+				AND(CategoryDataset.MatchCategoryID.IN(int32Expressions(ids)...)),
+		)
+		if _, err := stmt.Exec(c.db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // InsertDataset inserts multiple category dataset records into the 'category_dataset' table.
 // It takes a slice of model.CategoryDataset as input, excluding the 'id' column which is assumed to be auto-generated.
-// It returns an error if the insertion fails.
+// An empty slice is a no-op. It returns an error if the insertion fails.
 func (c *CategoryStore) InsertDataset(dataset []model.CategoryDataset) error {
+	if len(dataset) == 0 {
+		return nil
+	}
 	stmt := CategoryDataset.INSERT(CategoryDataset.AllColumns.Except(CategoryDataset.ID)).MODELS(dataset)
 	_, err := stmt.Exec(c.db)
 	if err != nil {
@@ -131,3 +237,89 @@ func (c *CategoryStore) InsertDataset(dataset []model.CategoryDataset) error {
 	}
 	return nil
 }
+
+// SaveSnapshot persists the given category tree as the taxonomy snapshot of a dataset version
+// into the 'category_snapshot' table. Any previous snapshot of the same version is removed first.
+// It returns an error if the deletion or insertion fails.
+func (c *CategoryStore) SaveSnapshot(version string, tree []transform.CategoryNode) error {
+	delStmt := CategorySnapshot.DELETE().WHERE(CategorySnapshot.Version.EQ(String(version)))
+	if _, err := delStmt.Exec(c.db); err != nil {
+		return err
+	}
+	if len(tree) == 0 {
+		return nil
+	}
+
+	rows := make([]model.CategorySnapshot, 0, len(tree))
+	for _, n := range tree {
+		rows = append(rows, model.CategorySnapshot{
+			Version:    version,
+			CategoryID: n.ID,
+			Name:       n.Name,
+			ParentID:   n.ParentID,
+			HasChild:   n.HasChild,
+			Path:       n.Path,
+		})
+	}
+	stmt := CategorySnapshot.INSERT(
+		CategorySnapshot.AllColumns.Except(CategorySnapshot.ID, CategorySnapshot.CreatedAt),
+	).MODELS(rows)
+	if _, err := stmt.Exec(c.db); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Snapshot retrieves the taxonomy snapshot stored for a dataset version, ordered by category ID.
+// It returns an empty slice if no snapshot exists for the version, or an error if the query fails.
+func (c *CategoryStore) Snapshot(version string) ([]transform.CategoryNode, error) {
+	stmt := SELECT(
+		CategorySnapshot.AllColumns,
+	).FROM(
+		CategorySnapshot,
+	).WHERE(
+		CategorySnapshot.Version.EQ(String(version)),
+	).ORDER_BY(
+		CategorySnapshot.CategoryID.ASC(),
+	)
+
+	var dest []model.CategorySnapshot
+	if err := stmt.Query(c.db, &dest); err != nil {
+		return nil, err
+	}
+
+	tree := make([]transform.CategoryNode, 0, len(dest))
+	for _, v := range dest {
+		tree = append(tree, transform.CategoryNode{
+			ID:       v.CategoryID,
+			Name:     v.Name,
+			ParentID: v.ParentID,
+			HasChild: v.HasChild,
+			Path:     v.Path,
+		})
+	}
+	return tree, nil
+}
+
+// CategoryRemap retrieves the old-to-new category ID mapping from the 'category_remap' table.
+// The mapping is maintained when categories are renamed, merged or moved so that existing
+// 'match_category' rows keep pointing at a valid category.
+// It returns an error if the query fails.
+func (c *CategoryStore) CategoryRemap() (transform.Remap, error) {
+	stmt := SELECT(
+		CategoryRemap.AllColumns,
+	).FROM(
+		CategoryRemap,
+	)
+
+	var dest []model.CategoryRemap
+	if err := stmt.Query(c.db, &dest); err != nil {
+		return nil, err
+	}
+
+	remap := make(transform.Remap, len(dest))
+	for _, v := range dest {
+		remap[v.OldCategoryID] = v.NewCategoryID
+	}
+	return remap, nil
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/opplieam/bb-transform/.jetgen/postgres/public/table"
+
+	"github.com/opplieam/bb-transform/internal/transform"
+)
+
+// SaveCheckpoint creates or replaces the checkpoint of an interrupted generation in the 'generation_checkpoint' table.
+// The assignments and categories of the checkpoint are stored as JSON.
+// It returns an error if the upsert fails.
+func (c *CategoryStore) SaveCheckpoint(cp transform.Checkpoint) error {
+	assignments, err := json.Marshal(cp.Assignments)
+	if err != nil {
+		return err
+	}
+	categories, err := json.Marshal(cp.Categories)
+	if err != nil {
+		return err
+	}
+	stmt := GenerationCheckpoint.INSERT(
+		GenerationCheckpoint.Version, GenerationCheckpoint.Seed, GenerationCheckpoint.AfterID,
+		GenerationCheckpoint.UpToID, GenerationCheckpoint.Incremental, GenerationCheckpoint.RowsWritten,
+		GenerationCheckpoint.Assignments, GenerationCheckpoint.Categories,
+	).VALUES(
+		cp.Version, cp.Seed, cp.AfterID, cp.UpToID, cp.Incremental, int32(cp.RowsWritten), //nolint:gosec // row counts fit in int32
+		string(assignments), string(categories),
+	).ON_CONFLICT(
+		GenerationCheckpoint.Version,
+	).DO_UPDATE(
+		SET(
+			GenerationCheckpoint.Seed.SET(GenerationCheckpoint.EXCLUDED.Seed),
+			GenerationCheckpoint.AfterID.SET(GenerationCheckpoint.EXCLUDED.AfterID),
+			GenerationCheckpoint.UpToID.SET(GenerationCheckpoint.EXCLUDED.UpToID),
+			GenerationCheckpoint.Incremental.SET(GenerationCheckpoint.EXCLUDED.Incremental),
+			GenerationCheckpoint.RowsWritten.SET(GenerationCheckpoint.EXCLUDED.RowsWritten),
+			GenerationCheckpoint.Assignments.SET(GenerationCheckpoint.EXCLUDED.Assignments),
+			GenerationCheckpoint.Categories.SET(GenerationCheckpoint.EXCLUDED.Categories),
+			GenerationCheckpoint.UpdatedAt.SET(LOCALTIMESTAMP()),
+		),
+	)
+	if _, err = stmt.Exec(c.db); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Checkpoint retrieves the checkpoint of an interrupted generation from the 'generation_checkpoint' table.
+// It returns transform.ErrCheckpointNotFound if there is none, or an error if the query fails.
+func (c *CategoryStore) Checkpoint(version string) (transform.Checkpoint, error) {
+	stmt := SELECT(
+		GenerationCheckpoint.AllColumns,
+	).FROM(
+		GenerationCheckpoint,
+	).WHERE(
+		GenerationCheckpoint.Version.EQ(String(version)),
+	)
+
+	var dest model.GenerationCheckpoint
+	if err := stmt.Query(c.db, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return transform.Checkpoint{}, transform.ErrCheckpointNotFound
+		}
+		return transform.Checkpoint{}, err
+	}
+	cp := transform.Checkpoint{
+		Version:     dest.Version,
+		Seed:        dest.Seed,
+		AfterID:     dest.AfterID,
+		UpToID:      dest.UpToID,
+		Incremental: dest.Incremental,
+		RowsWritten: int(dest.RowsWritten),
+		UpdatedAt:   dest.UpdatedAt,
+	}
+	if err := json.Unmarshal([]byte(dest.Assignments), &cp.Assignments); err != nil {
+		return transform.Checkpoint{}, err
+	}
+	if err := json.Unmarshal([]byte(dest.Categories), &cp.Categories); err != nil {
+		return transform.Checkpoint{}, err
+	}
+	return cp, nil
+}
+
+// DeleteCheckpoint removes the checkpoint of a version from the 'generation_checkpoint' table.
+// It returns an error if the deletion fails.
+func (c *CategoryStore) DeleteCheckpoint(version string) error {
+	stmt := GenerationCheckpoint.DELETE().WHERE(
+		GenerationCheckpoint.Version.EQ(String(version)),
+	)
+	if _, err := stmt.Exec(c.db); err != nil {
+		return err
+	}
+	return nil
+}
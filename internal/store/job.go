@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/lib/pq"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/opplieam/bb-transform/.jetgen/postgres/public/table"
+)
+
+// Job statuses recorded in the 'job_ledger' table.
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// uniqueViolation is the Postgres error code of a unique constraint violation.
+const uniqueViolation = "23505"
+
+var (
+	ErrJobDone       = errors.New("job already succeeded")
+	ErrJobInProgress = errors.New("job already in progress")
+	ErrVersionBusy   = errors.New("another job is running for the same version")
+)
+
+// JobStore provides methods for the 'job_ledger' table, which records every job message by its idempotency key
+// so that redelivered messages are not processed twice.
+type JobStore struct {
+	db *sql.DB
+}
+
+// NewJobStore creates a new instance of JobStore.
+// It takes a *sql.DB connection as input and returns a pointer to a JobStore.
+func NewJobStore(db *sql.DB) *JobStore {
+	return &JobStore{
+		db: db,
+	}
+}
+
+// JobLedgerResult is a 'job_ledger' row together with whether it is still within its lease.
+type JobLedgerResult struct {
+	model.JobLedger
+	Live bool `alias:"job_ledger.live"`
+}
+
+// StartJob records that the job with the given key is running.
+// A job that failed before, or whose running lease has expired (e.g. because the Lambda timed out), is started again.
+// It returns ErrJobDone if the job already succeeded, ErrJobInProgress if it is running within its lease,
+// ErrVersionBusy if another job for the same non-empty version is running within its lease,
+// or an error if any of the queries fail.
+// Jobs for the same version are serialized with a transaction-level advisory lock, so two jobs cannot both start.
+func (j *JobStore) StartJob(key, messageID, jobType, version string, lease time.Duration) error {
+	tx, err := j.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if version != "" {
+		lock := RawStatement("SELECT pg_advisory_xact_lock(hashtext(#lock_key))", RawArgs{"#lock_key": "job_ledger:" + version})
+		if _, err = lock.Exec(tx); err != nil {
+			return err
+		}
+	}
+
+	live := JobLedger.UpdatedAt.GT(LOCALTIMESTAMP().SUB(INTERVALd(lease)))
+	keyStmt := SELECT(
+		JobLedger.AllColumns, live.AS("job_ledger.live"),
+	).FROM(
+		JobLedger,
+	).WHERE(
+		JobLedger.Key.EQ(String(key)),
+	).FOR(
+		UPDATE(),
+	)
+	var existing JobLedgerResult
+	err = keyStmt.Query(tx, &existing)
+	found := err == nil
+	if err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return err
+	}
+	if found {
+		switch {
+		case existing.Status == JobStatusSucceeded:
+			return ErrJobDone
+		case existing.Status == JobStatusRunning && existing.Live:
+			return ErrJobInProgress
+		}
+	}
+
+	if version != "" {
+		busyStmt := SELECT(
+			JobLedger.Key,
+		).FROM(
+			JobLedger,
+		).WHERE(
+			AND(
+				JobLedger.Version.EQ(String(version)),
+				JobLedger.Status.EQ(String(JobStatusRunning)),
+				JobLedger.Key.NOT_EQ(String(key)),
+				live,
+			),
+		).LIMIT(1)
+		var busy []model.JobLedger
+		if err = busyStmt.Query(tx, &busy); err != nil {
+			return err
+		}
+		if len(busy) > 0 {
+			return ErrVersionBusy
+		}
+	}
+
+	var stmt Statement
+	if found {
+		stmt = JobLedger.UPDATE(
+			JobLedger.MessageID, JobLedger.Status, JobLedger.Attempts, JobLedger.Error, JobLedger.UpdatedAt,
+		).SET(
+			String(messageID), String(JobStatusRunning), JobLedger.Attempts.ADD(Int32(1)), NULL, LOCALTIMESTAMP(),
+		).WHERE(
+			JobLedger.Key.EQ(String(key)),
+		)
+	} else {
+		stmt = JobLedger.INSERT(
+			JobLedger.Key, JobLedger.MessageID, JobLedger.JobType, JobLedger.Version, JobLedger.Status, JobLedger.Attempts,
+		).VALUES(
+			key, messageID, jobType, version, JobStatusRunning, 1,
+		)
+	}
+	if _, err = stmt.Exec(tx); err != nil {
+		// Another invocation inserted the same key concurrently.
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return ErrJobInProgress
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// FinishJob records the final status of a job, together with its error message for failed jobs.
+// It returns an error if the update fails.
+func (j *JobStore) FinishJob(key, status, errMsg string) error {
+	var errExp Expression = NULL
+	if errMsg != "" {
+		errExp = String(errMsg)
+	}
+	stmt := JobLedger.UPDATE(
+		JobLedger.Status, JobLedger.Error, JobLedger.UpdatedAt,
+	).SET(
+		String(status), errExp, LOCALTIMESTAMP(),
+	).WHERE(
+		JobLedger.Key.EQ(String(key)),
+	)
+	if _, err := stmt.Exec(j.db); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/go-jet/jet/v2/postgres"
+
+	"github.com/opplieam/bb-transform/internal/transform"
+)
+
+// lockPollInterval is how often LockVersion retries while waiting for a lock.
+const lockPollInterval = 250 * time.Millisecond
+
+// LockResult represents the result of a pg_try_advisory_lock call.
+type LockResult struct {
+	Locked bool `alias:"lock.locked"`
+}
+
+// versionLockKey hashes a dataset version into the 64-bit key of its Postgres advisory lock.
+func versionLockKey(version string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("dataset_version:" + version))
+	return int64(h.Sum64()) //nolint:gosec // Wrapping around is fine for a lock key
+}
+
+// LockVersion acquires a session-level Postgres advisory lock keyed by a hash of the version, so that only one
+// run can write a version at a time. A shared lock can be held by several runs at once (e.g. the partitions
+// of a fan-out generation) and excludes the exclusive lock, and the other way around.
+// The lock is held on a dedicated connection until the returned unlock function is called.
+// A wait of 0 fails immediately when the lock is taken; otherwise LockVersion retries until the wait has passed
+// or ctx is done. The lock is released even when ctx is done by then, so that it does not stay with the connection.
+// It returns transform.ErrVersionLocked if the lock could not be acquired, or an error if any of the queries fail.
+func (c *CategoryStore) LockVersion(
+	ctx context.Context, version string, shared bool, wait time.Duration,
+) (func() error, error) {
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lockFn, unlockFn := "pg_try_advisory_lock", "pg_advisory_unlock"
+	if shared {
+		lockFn, unlockFn = "pg_try_advisory_lock_shared", "pg_advisory_unlock_shared"
+	}
+	key := versionLockKey(version)
+	stmt := RawStatement("SELECT "+lockFn+"(#key) AS \"lock.locked\"", RawArgs{"#key": key})
+	deadline := time.Now().Add(wait)
+	for {
+		var dest LockResult
+		if err = stmt.QueryContext(ctx, conn, &dest); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		if dest.Locked {
+			break
+		}
+		if !time.Now().Add(lockPollInterval).Before(deadline) {
+			_ = conn.Close()
+			return nil, transform.ErrVersionLocked
+		}
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+
+	unlock := func() error {
+		defer conn.Close()
+		_, err := RawStatement("SELECT "+unlockFn+"(#key)", RawArgs{"#key": key}).
+			ExecContext(context.WithoutCancel(ctx), conn)
+		return err
+	}
+	return unlock, nil
+}
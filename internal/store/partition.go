@@ -0,0 +1,207 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/go-jet/jet/v2/postgres"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/opplieam/bb-transform/.jetgen/postgres/public/table"
+
+	"github.com/opplieam/bb-transform/internal/transform"
+)
+
+// MatchIDRangeResult represents the lowest and highest ID of the matched categories kept by a filter.
+type MatchIDRangeResult struct {
+	First int32 `alias:"match_range.first"`
+	Last  int32 `alias:"match_range.last"`
+}
+
+// MatchIDRange retrieves the lowest and highest ID of the matched categories in the 'match_category' table
+// kept by the filter, or zeros if there are none.
+// It returns an error if the query fails.
+func (c *CategoryStore) MatchIDRange(filter transform.MatchFilter) (int32, int32, error) {
+	stmt := SELECT(
+		COALESCE(MINi(MatchCategory.ID), Int32(0)).AS("match_range.first"),
+		COALESCE(MAXi(MatchCategory.ID), Int32(0)).AS("match_range.last"),
+	).FROM(
+		MatchCategory,
+	).WHERE(
+		matchCondition(filter),
+	)
+
+	var dest MatchIDRangeResult
+	if err := stmt.Query(c.db, &dest); err != nil {
+		return 0, 0, err
+	}
+	return dest.First, dest.Last, nil
+}
+
+// SavePartitions replaces the partitions of a fan-out generation in the 'dataset_partition' table
+// with the given ones, all pending and recorded under the ID and seed of the fan-out,
+// and marks the version as building in the 'dataset_version' table within a single transaction.
+// It returns an error if any of the statements fail.
+func (c *CategoryStore) SavePartitions(fo transform.FanOutInfo) error {
+	tx, err := c.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	insert := DatasetPartition.INSERT(
+		DatasetPartition.Version, DatasetPartition.PartitionIndex, DatasetPartition.AfterID,
+		DatasetPartition.UpToID, DatasetPartition.Status, DatasetPartition.Rows,
+		DatasetPartition.FanOutID, DatasetPartition.Seed,
+	)
+	for _, p := range fo.Partitions {
+		insert = insert.VALUES(
+			fo.Version, int32(p.Index), p.AfterID, p.UpToID, transform.PartitionStatusPending, 0, //nolint:gosec // partition counts fit in int32
+			fo.ID, fo.Seed,
+		)
+	}
+	stmts := []Statement{
+		DatasetPartition.DELETE().WHERE(DatasetPartition.Version.EQ(String(fo.Version))),
+		insert,
+		DatasetVersion.INSERT(
+			DatasetVersion.Version, DatasetVersion.LastMatchID, DatasetVersion.Status,
+		).VALUES(
+			fo.Version, 0, transform.VersionStatusBuilding,
+		).ON_CONFLICT(
+			DatasetVersion.Version,
+		).DO_UPDATE(
+			SET(
+				DatasetVersion.LastMatchID.SET(DatasetVersion.EXCLUDED.LastMatchID),
+				DatasetVersion.Status.SET(DatasetVersion.EXCLUDED.Status),
+				DatasetVersion.UpdatedAt.SET(LOCALTIMESTAMP()),
+			),
+		),
+	}
+	for _, stmt := range stmts {
+		if _, err = stmt.Exec(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// FanOutInfo retrieves the fan-out generation recorded under the ID from the 'dataset_partition' table.
+// It returns transform.ErrPartitionsNotFound if no partitions are recorded under the ID, or an error if the query fails.
+func (c *CategoryStore) FanOutInfo(id string) (transform.FanOutInfo, error) {
+	stmt := SELECT(
+		DatasetPartition.AllColumns,
+	).FROM(
+		DatasetPartition,
+	).WHERE(
+		DatasetPartition.FanOutID.EQ(String(id)),
+	).ORDER_BY(
+		DatasetPartition.PartitionIndex.ASC(),
+	)
+
+	var dest []model.DatasetPartition
+	if err := stmt.Query(c.db, &dest); err != nil {
+		return transform.FanOutInfo{}, err
+	}
+	if len(dest) == 0 {
+		return transform.FanOutInfo{}, fmt.Errorf("%w: fan-out %s", transform.ErrPartitionsNotFound, id)
+	}
+	fo := transform.FanOutInfo{ID: id, Version: dest[0].Version, Seed: dest[0].Seed}
+	for _, p := range dest {
+		fo.Partitions = append(fo.Partitions, transform.Partition{Index: int(p.PartitionIndex), AfterID: p.AfterID, UpToID: p.UpToID})
+	}
+	return fo, nil
+}
+
+// CleanUpPartition removes the rows of a version in the 'category_dataset' table that were generated
+// from the matched categories of the partition, e.g. by a previous attempt of the partition job.
+// It returns an error if the deletion fails.
+func (c *CategoryStore) CleanUpPartition(version string, p transform.Partition) error {
+	condition := CategoryDataset.Version.EQ(String(version))
+	if p.AfterID > 0 {
+		condition = condition.AND(CategoryDataset.MatchCategoryID.GT(Int32(p.AfterID)))
+	}
+	if p.UpToID > 0 {
+		condition = condition.AND(CategoryDataset.MatchCategoryID.LT_EQ(Int32(p.UpToID)))
+	}
+	stmt := CategoryDataset.DELETE().WHERE(condition)
+	if _, err := stmt.Exec(c.db); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FinishPartition marks a partition in the 'dataset_partition' table as succeeded with the number of rows written
+// and returns how many partitions of the version are still pending.
+// Partitions of the same version finish one at a time (serialized with a transaction-level advisory lock),
+// so exactly one of them sees no pending partition left.
+// It returns an error if any of the queries fail.
+func (c *CategoryStore) FinishPartition(version string, index, rows int) (int, error) {
+	tx, err := c.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	lock := RawStatement("SELECT pg_advisory_xact_lock(hashtext(#lock_key))", RawArgs{"#lock_key": "dataset_partition:" + version})
+	if _, err = lock.Exec(tx); err != nil {
+		return 0, err
+	}
+
+	update := DatasetPartition.UPDATE(
+		DatasetPartition.Status, DatasetPartition.Rows, DatasetPartition.UpdatedAt,
+	).SET(
+		String(transform.PartitionStatusSucceeded), Int32(int32(rows)), LOCALTIMESTAMP(), //nolint:gosec // row counts fit in int32
+	).WHERE(
+		DatasetPartition.Version.EQ(String(version)).AND(DatasetPartition.PartitionIndex.EQ(Int32(int32(index)))), //nolint:gosec // partition counts fit in int32
+	)
+	if _, err = update.Exec(tx); err != nil {
+		return 0, err
+	}
+
+	countStmt := SELECT(
+		COUNT(STAR).AS("dataset_count.rows"),
+	).FROM(
+		DatasetPartition,
+	).WHERE(
+		DatasetPartition.Version.EQ(String(version)).AND(DatasetPartition.Status.NOT_EQ(String(transform.PartitionStatusSucceeded))),
+	)
+	var pending DatasetCountResult
+	if err = countStmt.Query(tx, &pending); err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(pending.Rows), nil
+}
+
+// Partitions retrieves the partitions of a fan-out generation from the 'dataset_partition' table, ordered by index.
+// It returns an error if the query fails.
+func (c *CategoryStore) Partitions(version string) ([]transform.PartitionInfo, error) {
+	stmt := SELECT(
+		DatasetPartition.AllColumns,
+	).FROM(
+		DatasetPartition,
+	).WHERE(
+		DatasetPartition.Version.EQ(String(version)),
+	).ORDER_BY(
+		DatasetPartition.PartitionIndex.ASC(),
+	)
+
+	var dest []model.DatasetPartition
+	if err := stmt.Query(c.db, &dest); err != nil {
+		return nil, err
+	}
+	parts := make([]transform.PartitionInfo, 0, len(dest))
+	for _, p := range dest {
+		parts = append(parts, transform.PartitionInfo{
+			Partition: transform.Partition{Index: int(p.PartitionIndex), AfterID: p.AfterID, UpToID: p.UpToID},
+			Status:    p.Status,
+			Rows:      int(p.Rows),
+			UpdatedAt: p.UpdatedAt,
+		})
+	}
+	return parts, nil
+}
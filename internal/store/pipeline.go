@@ -0,0 +1,52 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/opplieam/bb-transform/.jetgen/postgres/public/table"
+)
+
+var (
+	ErrNotFound = errors.New("record not found")
+)
+
+// PipelineStore provides methods for reading named pipeline definitions from the 'pipeline_definition' table.
+type PipelineStore struct {
+	db *sql.DB
+}
+
+// NewPipelineStore creates a new instance of PipelineStore.
+// It takes a *sql.DB connection as input and returns a pointer to a PipelineStore.
+func NewPipelineStore(db *sql.DB) *PipelineStore {
+	return &PipelineStore{
+		db: db,
+	}
+}
+
+// PipelineDefinition retrieves the raw YAML/JSON definition of the pipeline with the given name.
+// It returns ErrNotFound if no definition with that name exists, or an error if the query fails.
+func (p *PipelineStore) PipelineDefinition(name string) ([]byte, error) {
+	stmt := SELECT(
+		PipelineDefinition.AllColumns,
+	).FROM(
+		PipelineDefinition,
+	).WHERE(
+		PipelineDefinition.Name.EQ(String(name)),
+	)
+
+	var dest model.PipelineDefinition
+	if err := stmt.Query(p.db, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return []byte(dest.Definition), nil
+}
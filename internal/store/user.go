@@ -0,0 +1,50 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/opplieam/bb-transform/.jetgen/postgres/public/table"
+
+	"github.com/opplieam/bb-transform/internal/auth"
+)
+
+// UserStore provides read access to the 'users' table used to authenticate admin requests.
+type UserStore struct {
+	db *sql.DB
+}
+
+// NewUserStore creates a new instance of UserStore.
+// It takes a *sql.DB connection as input and returns a pointer to a UserStore.
+func NewUserStore(db *sql.DB) *UserStore {
+	return &UserStore{
+		db: db,
+	}
+}
+
+// UserByUsername retrieves a user by username from the 'users' table.
+// It returns auth.ErrUserNotFound if there is no such user, or an error if the query fails.
+func (u *UserStore) UserByUsername(username string) (model.Users, error) {
+	stmt := SELECT(
+		Users.AllColumns,
+	).FROM(
+		Users,
+	).WHERE(
+		Users.Username.EQ(String(username)),
+	)
+
+	var dest model.Users
+	if err := stmt.Query(u.db, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return model.Users{}, auth.ErrUserNotFound
+		}
+		return model.Users{}, err
+	}
+	return dest, nil
+}
@@ -0,0 +1,350 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	//nolint:revive,stylecheck // simulate SQL
+	. "github.com/opplieam/bb-transform/.jetgen/postgres/public/table"
+
+	"github.com/opplieam/bb-transform/internal/transform"
+)
+
+// VersionInfo retrieves the bookkeeping row of a dataset version from the 'dataset_version' table.
+// It returns transform.ErrVersionNotFound if the version was never generated, or an error if the query fails.
+func (c *CategoryStore) VersionInfo(version string) (transform.VersionInfo, error) {
+	stmt := SELECT(
+		DatasetVersion.AllColumns,
+	).FROM(
+		DatasetVersion,
+	).WHERE(
+		DatasetVersion.Version.EQ(String(version)),
+	)
+
+	var dest model.DatasetVersion
+	if err := stmt.Query(c.db, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return transform.VersionInfo{}, transform.ErrVersionNotFound
+		}
+		return transform.VersionInfo{}, err
+	}
+	return toVersionInfo(dest), nil
+}
+
+// ReserveVersion inserts the bookkeeping row of a new dataset version into the 'dataset_version' table
+// with the status building, unless a version with the name already exists.
+// It reports whether the row was inserted, or returns an error if the insert fails.
+func (c *CategoryStore) ReserveVersion(version string) (bool, error) {
+	stmt := DatasetVersion.INSERT(
+		DatasetVersion.Version, DatasetVersion.LastMatchID, DatasetVersion.Status,
+	).VALUES(
+		version, 0, transform.VersionStatusBuilding,
+	).ON_CONFLICT(
+		DatasetVersion.Version,
+	).DO_NOTHING()
+	res, err := stmt.Exec(c.db)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// SaveVersion creates or updates the bookkeeping row of a dataset version in the 'dataset_version' table,
+// recording the last processed match_category ID and the user who generated it (NULL when actor is empty).
+// New versions and versions reserved by ReserveVersion become active; the status of other existing versions
+// is left untouched, and so is their immutable flag.
+// An empty actor keeps the user recorded by a previous run.
+// It returns an error if the upsert fails.
+func (c *CategoryStore) SaveVersion(version string, lastMatchID int32, actor string) error {
+	var generatedBy Expression = NULL
+	if actor != "" {
+		generatedBy = String(actor)
+	}
+	stmt := DatasetVersion.INSERT(
+		DatasetVersion.Version, DatasetVersion.LastMatchID, DatasetVersion.Status, DatasetVersion.GeneratedBy,
+	).VALUES(
+		version, lastMatchID, transform.VersionStatusActive, generatedBy,
+	).ON_CONFLICT(
+		DatasetVersion.Version,
+	).DO_UPDATE(
+		SET(
+			DatasetVersion.LastMatchID.SET(DatasetVersion.EXCLUDED.LastMatchID),
+			DatasetVersion.Status.SET(StringExp(
+				CASE(DatasetVersion.Status).
+					WHEN(String(transform.VersionStatusBuilding)).THEN(DatasetVersion.EXCLUDED.Status).
+					ELSE(DatasetVersion.Status),
+			)),
+			DatasetVersion.GeneratedBy.SET(StringExp(COALESCE(DatasetVersion.EXCLUDED.GeneratedBy, DatasetVersion.GeneratedBy))),
+			DatasetVersion.UpdatedAt.SET(LOCALTIMESTAMP()),
+		),
+	)
+	if _, err := stmt.Exec(c.db); err != nil {
+		return err
+	}
+	return nil
+}
+
+// VersionNames retrieves the names of all dataset versions from the 'dataset_version' table.
+// It returns an error if the query fails.
+func (c *CategoryStore) VersionNames() ([]string, error) {
+	stmt := SELECT(
+		DatasetVersion.Version,
+	).FROM(
+		DatasetVersion,
+	)
+
+	var dest []model.DatasetVersion
+	if err := stmt.Query(c.db, &dest); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(dest))
+	for _, v := range dest {
+		names = append(names, v.Version)
+	}
+	return names, nil
+}
+
+func toVersionInfo(v model.DatasetVersion) transform.VersionInfo {
+	return transform.VersionInfo{
+		Version:     v.Version,
+		LastMatchID: v.LastMatchID,
+		Status:      v.Status,
+		Immutable:   v.Immutable,
+		GeneratedBy: v.GeneratedBy,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+	}
+}
+
+// DatasetCountResult represents the number of dataset rows per version and split label.
+type DatasetCountResult struct {
+	Version string `alias:"category_dataset.version"`
+	Label   string `alias:"category_dataset.label"`
+	Rows    int64  `alias:"dataset_count.rows"`
+}
+
+// ListVersions retrieves every dataset version from the 'dataset_version' table together with
+// the number of rows per split label in the 'category_dataset' table.
+// It returns an error if any of the queries fail.
+func (c *CategoryStore) ListVersions() ([]transform.VersionSummary, error) {
+	versionStmt := SELECT(
+		DatasetVersion.AllColumns,
+	).FROM(
+		DatasetVersion,
+	)
+	var versions []model.DatasetVersion
+	if err := versionStmt.Query(c.db, &versions); err != nil {
+		return nil, err
+	}
+
+	countStmt := SELECT(
+		CategoryDataset.Version, CategoryDataset.Label, COUNT(STAR).AS("dataset_count.rows"),
+	).FROM(
+		CategoryDataset,
+	).GROUP_BY(
+		CategoryDataset.Version, CategoryDataset.Label,
+	)
+	var counts []DatasetCountResult
+	if err := countStmt.Query(c.db, &counts); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]transform.VersionSummary, 0, len(versions))
+	index := make(map[string]int, len(versions))
+	for i, v := range versions {
+		index[v.Version] = i
+		summaries = append(summaries, transform.VersionSummary{
+			VersionInfo: toVersionInfo(v),
+			Labels:      make(map[string]int64),
+		})
+	}
+	for _, v := range counts {
+		i, ok := index[v.Version]
+		if !ok {
+			continue
+		}
+		summaries[i].Rows += v.Rows
+		summaries[i].Labels[v.Label] = v.Rows
+	}
+	return summaries, nil
+}
+
+// SetVersionStatus updates the status of a dataset version in the 'dataset_version' table.
+// It returns transform.ErrVersionNotFound if the version does not exist, or an error if the update fails.
+func (c *CategoryStore) SetVersionStatus(version, status string) error {
+	stmt := DatasetVersion.UPDATE(
+		DatasetVersion.Status, DatasetVersion.UpdatedAt,
+	).SET(
+		String(status), LOCALTIMESTAMP(),
+	).WHERE(
+		DatasetVersion.Version.EQ(String(version)),
+	)
+	res, err := stmt.Exec(c.db)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return transform.ErrVersionNotFound
+	}
+	return nil
+}
+
+// SetVersionImmutable sets or clears the immutable flag of a dataset version in the 'dataset_version' table.
+// It returns transform.ErrVersionNotFound if the version does not exist, or an error if the update fails.
+func (c *CategoryStore) SetVersionImmutable(version string, immutable bool) error {
+	stmt := DatasetVersion.UPDATE(
+		DatasetVersion.Immutable, DatasetVersion.UpdatedAt,
+	).SET(
+		Bool(immutable), LOCALTIMESTAMP(),
+	).WHERE(
+		DatasetVersion.Version.EQ(String(version)),
+	)
+	res, err := stmt.Exec(c.db)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return transform.ErrVersionNotFound
+	}
+	return nil
+}
+
+// Dataset retrieves all rows of a dataset version from the 'category_dataset' table, ordered by ID.
+// It returns an error if the query fails.
+func (c *CategoryStore) Dataset(version string) ([]model.CategoryDataset, error) {
+	stmt := SELECT(
+		CategoryDataset.AllColumns,
+	).FROM(
+		CategoryDataset,
+	).WHERE(
+		CategoryDataset.Version.EQ(String(version)),
+	).ORDER_BY(
+		CategoryDataset.ID.ASC(),
+	)
+
+	var dest []model.CategoryDataset
+	if err := stmt.Query(c.db, &dest); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// EachDatasetRow streams the rows of a dataset version from the 'category_dataset' table to fn, ordered by ID,
+// without loading them all into memory. When label is not empty, only the rows with that split label are read.
+// It stops at the first error returned by fn, and returns an error if the query fails.
+func (c *CategoryStore) EachDatasetRow(
+	ctx context.Context, version, label string, fn func(model.CategoryDataset) error,
+) error {
+	condition := CategoryDataset.Version.EQ(String(version))
+	if label != "" {
+		condition = condition.AND(CategoryDataset.Label.EQ(String(label)))
+	}
+	stmt := SELECT(
+		CategoryDataset.AllColumns,
+	).FROM(
+		CategoryDataset,
+	).WHERE(
+		condition,
+	).ORDER_BY(
+		CategoryDataset.ID.ASC(),
+	)
+
+	rows, err := stmt.Rows(ctx, c.db)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var dest model.CategoryDataset
+		if err = rows.Scan(&dest); err != nil {
+			return err
+		}
+		if err = fn(dest); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// DeleteVersion removes a dataset version together with its rows in 'category_dataset', its taxonomy
+// snapshot in 'category_snapshot', the aliases in 'dataset_alias' pointing to it, its pending checkpoint
+// in 'generation_checkpoint' and its partitions in 'dataset_partition' within a single transaction.
+// It returns an error if any of the deletions fail.
+func (c *CategoryStore) DeleteVersion(version string) error {
+	tx, err := c.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, stmt := range deleteVersionStatements(version) {
+		if _, err = stmt.Exec(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ReleaseVersion removes a dataset version reserved by ReserveVersion, like DeleteVersion, as long as it still has
+// the status building. A version that has been completed in the meantime is kept.
+// It returns an error if any of the queries fail.
+func (c *CategoryStore) ReleaseVersion(version string) error {
+	tx, err := c.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt := SELECT(
+		DatasetVersion.Status,
+	).FROM(
+		DatasetVersion,
+	).WHERE(
+		DatasetVersion.Version.EQ(String(version)),
+	).FOR(UPDATE())
+	var dest model.DatasetVersion
+	if err = stmt.Query(tx, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if dest.Status != transform.VersionStatusBuilding {
+		return nil
+	}
+	for _, stmt := range deleteVersionStatements(version) {
+		if _, err = stmt.Exec(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// deleteVersionStatements returns the statements removing a version and everything recorded for it.
+func deleteVersionStatements(version string) []Statement {
+	return []Statement{
+		CategoryDataset.DELETE().WHERE(CategoryDataset.Version.EQ(String(version))),
+		CategorySnapshot.DELETE().WHERE(CategorySnapshot.Version.EQ(String(version))),
+		DatasetAlias.DELETE().WHERE(DatasetAlias.Version.EQ(String(version))),
+		GenerationCheckpoint.DELETE().WHERE(GenerationCheckpoint.Version.EQ(String(version))),
+		DatasetPartition.DELETE().WHERE(DatasetPartition.Version.EQ(String(version))),
+		DatasetVersion.DELETE().WHERE(DatasetVersion.Version.EQ(String(version))),
+	}
+}
@@ -0,0 +1,116 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Well-known alias names. Any other non-empty name may be used as well.
+const (
+	AliasLatest     = "latest"
+	AliasProduction = "production"
+)
+
+var (
+	ErrAliasNotFound = errors.New("dataset alias not found")
+	ErrInvalidAlias  = errors.New("invalid dataset alias")
+	ErrAliasTarget   = errors.New("aliases can only point to active dataset versions")
+)
+
+// Alias is a named pointer to a dataset version, so that training jobs can read "production"
+// instead of hard-coding a version string.
+type Alias struct {
+	Name      string    `json:"name"`
+	Version   string    `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AliasStorer defines the storage operations for dataset version aliases.
+// MoveAliases must point every given alias at the version atomically: either all of them move or none.
+type AliasStorer interface {
+	VersionInfo(version string) (VersionInfo, error)
+	MoveAliases(version string, names []string) error
+	Alias(name string) (Alias, error)
+	ListAliases() ([]Alias, error)
+	DeleteAlias(name string) error
+}
+
+// Aliases manages named aliases pointing to dataset versions.
+type Aliases struct {
+	log   *slog.Logger
+	store AliasStorer
+}
+
+// NewAliases creates a new Aliases instance.
+func NewAliases(l *slog.Logger, as AliasStorer) *Aliases {
+	return &Aliases{
+		log:   l.With("component", "aliases"),
+		store: as,
+	}
+}
+
+// Set points the aliases at the version, creating the ones that do not exist yet.
+// It returns ErrVersionNotFound without moving any alias if the version was never generated,
+// and ErrAliasTarget if it is not active (e.g. archived, retired or still being built).
+func (a *Aliases) Set(version string, names ...string) error {
+	if version == "" {
+		return fmt.Errorf("%w: version is required", ErrInvalidAlias)
+	}
+	if err := validateAliases(names); err != nil {
+		return err
+	}
+	info, err := a.store.VersionInfo(version)
+	if err != nil {
+		return err
+	}
+	if info.Status != VersionStatusActive {
+		return fmt.Errorf("%w: %s is %s", ErrAliasTarget, version, info.Status)
+	}
+	if err = a.store.MoveAliases(version, names); err != nil {
+		return err
+	}
+	a.log.Info("moved aliases", "aliases", names, "version", version)
+	return nil
+}
+
+// Resolve returns the version the alias points to, or ErrAliasNotFound.
+func (a *Aliases) Resolve(name string) (string, error) {
+	alias, err := a.store.Alias(name)
+	if err != nil {
+		return "", err
+	}
+	return alias.Version, nil
+}
+
+// List returns every alias.
+func (a *Aliases) List() ([]Alias, error) {
+	return a.store.ListAliases()
+}
+
+// Delete removes an alias. The version it points to is kept.
+func (a *Aliases) Delete(name string) error {
+	if err := a.store.DeleteAlias(name); err != nil {
+		return err
+	}
+	a.log.Info("deleted alias", "alias", name)
+	return nil
+}
+
+func validateAliases(names []string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("%w: no alias given", ErrInvalidAlias)
+	}
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if name == "" {
+			return fmt.Errorf("%w: empty name", ErrInvalidAlias)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("%w: duplicate name %q", ErrInvalidAlias, name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package transform
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasesSet(t *testing.T) {
+	type mockBehavior func(storer *MockAliasStorer)
+	tests := []struct {
+		name         string
+		version      string
+		aliases      []string
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:    "Success",
+			version: "v2",
+			aliases: []string{AliasLatest, AliasProduction},
+			mockBehavior: func(storer *MockAliasStorer) {
+				storer.EXPECT().VersionInfo("v2").Return(VersionInfo{Version: "v2", Status: VersionStatusActive}, nil)
+				storer.EXPECT().MoveAliases("v2", []string{AliasLatest, AliasProduction}).Return(nil)
+			},
+		},
+		{
+			name:    "Unknown version",
+			version: "v9",
+			aliases: []string{AliasProduction},
+			mockBehavior: func(storer *MockAliasStorer) {
+				storer.EXPECT().VersionInfo("v9").Return(VersionInfo{}, ErrVersionNotFound)
+			},
+			wantErr: ErrVersionNotFound,
+		},
+		{
+			name:    "Archived version",
+			version: "v1",
+			aliases: []string{AliasProduction},
+			mockBehavior: func(storer *MockAliasStorer) {
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1", Status: VersionStatusArchived}, nil)
+			},
+			wantErr: ErrAliasTarget,
+		},
+		{
+			name:    "Building version",
+			version: "v3",
+			aliases: []string{AliasProduction},
+			mockBehavior: func(storer *MockAliasStorer) {
+				storer.EXPECT().VersionInfo("v3").Return(VersionInfo{Version: "v3", Status: VersionStatusBuilding}, nil)
+			},
+			wantErr: ErrAliasTarget,
+		},
+		{
+			name:         "Missing version",
+			aliases:      []string{AliasLatest},
+			mockBehavior: func(storer *MockAliasStorer) {},
+			wantErr:      ErrInvalidAlias,
+		},
+		{
+			name:         "Duplicate alias",
+			version:      "v2",
+			aliases:      []string{AliasLatest, AliasLatest},
+			mockBehavior: func(storer *MockAliasStorer) {},
+			wantErr:      ErrInvalidAlias,
+		},
+		{
+			name:         "No alias",
+			version:      "v2",
+			mockBehavior: func(storer *MockAliasStorer) {},
+			wantErr:      ErrInvalidAlias,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorer := NewMockAliasStorer(t)
+			tt.mockBehavior(mockStorer)
+
+			err := NewAliases(testLogger(), mockStorer).Set(tt.version, tt.aliases...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAliasesResolve(t *testing.T) {
+	mockStorer := NewMockAliasStorer(t)
+	mockStorer.EXPECT().Alias(AliasProduction).Return(Alias{Name: AliasProduction, Version: "v3"}, nil)
+	mockStorer.EXPECT().Alias("missing").Return(Alias{}, ErrAliasNotFound)
+
+	aliases := NewAliases(testLogger(), mockStorer)
+	version, err := aliases.Resolve(AliasProduction)
+	require.NoError(t, err)
+	assert.Equal(t, "v3", version)
+
+	_, err = aliases.Resolve("missing")
+	assert.True(t, errors.Is(err, ErrAliasNotFound))
+}
@@ -0,0 +1,129 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+)
+
+// DeadlineMargin is how much time must be left before the context deadline to write another batch.
+// When less is left, the write stage checkpoints its progress and stops with ErrDeadlineReached.
+const DeadlineMargin = 5 * time.Second
+
+// insertBatchSize is the number of dataset rows inserted at once by the write stage.
+const insertBatchSize = 1000
+
+var (
+	ErrDeadlineReached    = errors.New("deadline reached, generation checkpointed")
+	ErrCheckpointNotFound = errors.New("generation checkpoint not found")
+	ErrCheckpointStale    = errors.New("generation checkpoint no longer matches the source rows, regenerate the version")
+)
+
+// Checkpoint records how far an interrupted generation of a version got.
+// Assignments are the rows of the run in insertion order, and Categories the target categories they point to
+// as they were when the run started. A resumed run rebuilds its rows from the source rows with IDs
+// in (AfterID, UpToID] and these assignments instead of remapping, sampling and splitting again,
+// so the rows and their split labels do not depend on the order the source rows are read in, nor on
+// remaps or taxonomy changes in between. Only the rows after the first RowsWritten are inserted.
+type Checkpoint struct {
+	Version     string       `json:"version"`
+	Seed        int64        `json:"seed"`
+	AfterID     int32        `json:"after_id"`
+	UpToID      int32        `json:"up_to_id"`
+	Incremental bool         `json:"incremental"`
+	RowsWritten int          `json:"rows_written"`
+	Assignments []Assignment `json:"assignments"`
+	Categories  Category     `json:"categories"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Assignment is a row of a checkpointed run: the source match_category row, the target category it points to
+// after remapping and its split label.
+type Assignment struct {
+	MatchCategoryID int32  `json:"match_category_id"`
+	CategoryID      int32  `json:"category_id"`
+	Label           string `json:"label"`
+}
+
+// newCheckpoint captures the rows of the run, of which the first written have been inserted.
+// A resumed run keeps the assignments and categories of the checkpoint it was resumed from.
+func newCheckpoint(st *State, written int) Checkpoint {
+	cp := Checkpoint{
+		Version:     st.Version,
+		Seed:        st.Seed,
+		AfterID:     st.AfterID,
+		UpToID:      st.LastMatchID,
+		Incremental: st.Incremental,
+		RowsWritten: written,
+	}
+	if st.Resume != nil {
+		cp.Assignments, cp.Categories = st.Resume.Assignments, st.Resume.Categories
+		return cp
+	}
+	cp.Assignments = make([]Assignment, 0, len(st.Dataset))
+	cp.Categories = make(Category)
+	for _, v := range st.Dataset {
+		a := Assignment{CategoryID: v.CategoryIDOut, Label: v.Label}
+		if v.MatchCategoryID != nil {
+			a.MatchCategoryID = *v.MatchCategoryID
+		}
+		cp.Assignments = append(cp.Assignments, a)
+		cp.Categories[v.CategoryIDOut] = st.Original[v.CategoryIDOut]
+	}
+	return cp
+}
+
+// StageRestore is the stage that replaces filtering, sampling and splitting in a resumed run.
+// It is not accepted in Config.Stages.
+const StageRestore = "restore"
+
+// restoreStage rebuilds the samples of a resumed run from the assignments of its checkpoint,
+// and the target categories from the ones recorded with it.
+type restoreStage struct {
+	config Config
+}
+
+func (s *restoreStage) Name() string { return StageRestore }
+
+func (s *restoreStage) Run(st *State) error {
+	byID := make(map[int32]model.MatchCategory, len(st.Matched))
+	for _, v := range st.Matched {
+		byID[v.ID] = v
+	}
+	maxDepth := s.config.maxLevels()
+	policy := s.config.truncatePolicy()
+	st.Original = st.Resume.Categories
+	st.Samples = make([]Sample, 0, len(st.Resume.Assignments))
+	for _, a := range st.Resume.Assignments {
+		v, ok := byID[a.MatchCategoryID]
+		if !ok {
+			return fmt.Errorf("%w: match_category %d no longer exists", ErrCheckpointStale, a.MatchCategoryID)
+		}
+		levels, err := inputLevels(v)
+		if err != nil {
+			return fmt.Errorf("%w: match_category %d: %w", ErrCheckpointStale, a.MatchCategoryID, err)
+		}
+		levels, _, drop := truncateLevels(levels, maxDepth, policy)
+		if drop {
+			return fmt.Errorf("%w: match_category %d is too deep", ErrCheckpointStale, a.MatchCategoryID)
+		}
+		v.MatchID = &a.CategoryID
+		st.Samples = append(st.Samples, Sample{Match: v, Levels: levels, Label: a.Label})
+	}
+	return nil
+}
+
+// deadlineNear reports whether the run has less than DeadlineMargin left.
+func (st *State) deadlineNear(now time.Time) bool {
+	return !st.Deadline.IsZero() && st.Deadline.Sub(now) < DeadlineMargin
+}
+
+// Continuation returns the configuration that resumes this run from its checkpoint after GenerateDataset
+// returned ErrDeadlineReached. The version is the resolved one and the seed the one the run used.
+func (t *Transform) Continuation() Config {
+	cfg := t.config
+	cfg.Resume = true
+	return cfg
+}
@@ -0,0 +1,132 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+)
+
+// Path shapes control how input category levels are written into the dataset.
+// PathShapeColumns writes the fixed L1In-L8In columns, PathShapeJSON writes the LevelsIn JSON array
+// (L1In still receives the first level since it is mandatory), and PathShapeBoth writes both.
+const (
+	PathShapeColumns = "columns"
+	PathShapeJSON    = "json"
+	PathShapeBoth    = "both"
+)
+
+// Truncate policies decide what happens to input paths deeper than the allowed depth.
+// TruncateKeepFirst keeps the top-most levels, TruncateKeepLast keeps the deepest levels,
+// and TruncateDrop leaves the row out of the dataset.
+const (
+	TruncateKeepFirst = "keep_first"
+	TruncateKeepLast  = "keep_last"
+	TruncateDrop      = "drop"
+)
+
+// columnLevels is the number of fixed L1-L8 level columns.
+const columnLevels = 8
+
+var (
+	ErrInvalidPathShape      = errors.New("invalid path shape")
+	ErrInvalidTruncatePolicy = errors.New("invalid truncate policy")
+	ErrInvalidMaxDepth       = errors.New("invalid max depth: the columns and both path shapes hold at most 8 levels")
+)
+
+// DepthReport summarizes how input paths were handled with regard to the configured maximum depth.
+// Truncated counts rows whose path was shortened, Dropped counts rows left out because of TruncateDrop,
+// Invalid counts rows whose levels could not be read, Shallow counts rows with fewer levels than
+// the configured minimum input depth, and MaxDepth is the deepest input path seen.
+type DepthReport struct {
+	Truncated int
+	Dropped   int
+	Invalid   int
+	Shallow   int
+	MaxDepth  int
+}
+
+// inputLevels returns the input category levels of a matched category.
+// The Levels JSON array is preferred when present, otherwise the non-empty L1-L8 columns are used.
+func inputLevels(v model.MatchCategory) ([]string, error) {
+	if v.Levels != nil {
+		var levels []string
+		if err := json.Unmarshal([]byte(*v.Levels), &levels); err != nil {
+			return nil, err
+		}
+		if len(levels) == 0 {
+			return nil, errors.New("empty levels")
+		}
+		return levels, nil
+	}
+
+	levels := []string{v.L1}
+	for _, l := range []*string{v.L2, v.L3, v.L4, v.L5, v.L6, v.L7, v.L8} {
+		if l == nil {
+			break
+		}
+		levels = append(levels, *l)
+	}
+	return levels, nil
+}
+
+// maxLevels returns the effective maximum depth for the configuration.
+// A MaxDepth of 0 means unlimited, except for the shapes writing the fixed level columns, which cap it,
+// so that deeper paths go through the truncate policy instead of losing their last levels in the columns.
+func (c Config) maxLevels() int {
+	maxDepth := int(c.MaxDepth)
+	if c.pathShape() != PathShapeJSON && (maxDepth == 0 || maxDepth > columnLevels) {
+		maxDepth = columnLevels
+	}
+	return maxDepth
+}
+
+func (c Config) pathShape() string {
+	if c.PathShape == "" {
+		return PathShapeColumns
+	}
+	return c.PathShape
+}
+
+func (c Config) truncatePolicy() string {
+	if c.TruncatePolicy == "" {
+		return TruncateKeepFirst
+	}
+	return c.TruncatePolicy
+}
+
+// truncateLevels shortens levels to maxDepth according to policy.
+// It reports whether the levels were truncated and whether the row must be dropped instead.
+func truncateLevels(levels []string, maxDepth int, policy string) ([]string, bool, bool) {
+	if maxDepth == 0 || len(levels) <= maxDepth {
+		return levels, false, false
+	}
+	switch policy {
+	case TruncateDrop:
+		return nil, false, true
+	case TruncateKeepLast:
+		return levels[len(levels)-maxDepth:], true, false
+	default:
+		return levels[:maxDepth], true, false
+	}
+}
+
+// setLevels writes levels into the dataset row using the given path shape.
+func setLevels(row *model.CategoryDataset, levels []string, shape string) error {
+	row.L1In = levels[0]
+	if shape == PathShapeColumns || shape == PathShapeBoth {
+		cols := []**string{&row.L2In, &row.L3In, &row.L4In, &row.L5In, &row.L6In, &row.L7In, &row.L8In}
+		for i := 1; i < len(levels) && i < columnLevels; i++ {
+			*cols[i-1] = &levels[i]
+		}
+	}
+	if shape == PathShapeJSON || shape == PathShapeBoth {
+		b, err := json.Marshal(levels)
+		if err != nil {
+			return err
+		}
+		s := string(b)
+		row.LevelsIn = &s
+	}
+	return nil
+}
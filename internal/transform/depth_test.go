@@ -0,0 +1,111 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputLevels(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name    string
+		match   model.MatchCategory
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Columns",
+			match: model.MatchCategory{L1: "A", L2: str("B"), L3: str("C")},
+			want:  []string{"A", "B", "C"},
+		},
+		{
+			name:  "JSON levels preferred",
+			match: model.MatchCategory{L1: "A", Levels: str(`["A","B","C","D","E","F","G","H","I"]`)},
+			want:  []string{"A", "B", "C", "D", "E", "F", "G", "H", "I"},
+		},
+		{
+			name:    "Invalid JSON",
+			match:   model.MatchCategory{L1: "A", Levels: str(`not json`)},
+			wantErr: true,
+		},
+		{
+			name:    "Empty JSON",
+			match:   model.MatchCategory{L1: "A", Levels: str(`[]`)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inputLevels(tt.match)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTruncateLevels(t *testing.T) {
+	levels := []string{"A", "B", "C", "D"}
+	tests := []struct {
+		name          string
+		maxDepth      int
+		policy        string
+		want          []string
+		wantTruncated bool
+		wantDrop      bool
+	}{
+		{name: "Unlimited", maxDepth: 0, policy: TruncateKeepFirst, want: levels},
+		{name: "Within limit", maxDepth: 4, policy: TruncateDrop, want: levels},
+		{name: "Keep first", maxDepth: 2, policy: TruncateKeepFirst, want: []string{"A", "B"}, wantTruncated: true},
+		{name: "Keep last", maxDepth: 2, policy: TruncateKeepLast, want: []string{"C", "D"}, wantTruncated: true},
+		{name: "Drop", maxDepth: 2, policy: TruncateDrop, wantDrop: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated, drop := truncateLevels(levels, tt.maxDepth, tt.policy)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantTruncated, truncated)
+			assert.Equal(t, tt.wantDrop, drop)
+		})
+	}
+}
+
+func TestSetLevels(t *testing.T) {
+	levels := []string{"A", "B", "C"}
+
+	var columns model.CategoryDataset
+	require.NoError(t, setLevels(&columns, levels, PathShapeColumns))
+	assert.Equal(t, "A", columns.L1In)
+	assert.Equal(t, "C", *columns.L3In)
+	assert.Nil(t, columns.L4In)
+	assert.Nil(t, columns.LevelsIn)
+
+	var asJSON model.CategoryDataset
+	require.NoError(t, setLevels(&asJSON, levels, PathShapeJSON))
+	assert.Equal(t, "A", asJSON.L1In)
+	assert.Nil(t, asJSON.L2In)
+	assert.JSONEq(t, `["A","B","C"]`, *asJSON.LevelsIn)
+
+	var both model.CategoryDataset
+	require.NoError(t, setLevels(&both, levels, PathShapeBoth))
+	assert.Equal(t, "B", *both.L2In)
+	assert.JSONEq(t, `["A","B","C"]`, *both.LevelsIn)
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{PathShape: PathShapeBoth, TruncatePolicy: TruncateDrop}.Validate())
+	assert.ErrorIs(t, Config{PathShape: "xml"}.Validate(), ErrInvalidPathShape)
+	assert.ErrorIs(t, Config{TruncatePolicy: "middle"}.Validate(), ErrInvalidTruncatePolicy)
+	assert.NoError(t, Config{PathShape: PathShapeJSON, MaxDepth: 12}.Validate())
+	assert.ErrorIs(t, Config{MaxDepth: 9}.Validate(), ErrInvalidMaxDepth)
+	assert.ErrorIs(t, Config{PathShape: PathShapeBoth, MaxDepth: 9}.Validate(), ErrInvalidMaxDepth)
+}
@@ -0,0 +1,136 @@
+package transform
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+)
+
+// Export formats supported by ExportDataset.
+const (
+	ExportFormatJSONL = "jsonl"
+	ExportFormatCSV   = "csv"
+)
+
+var (
+	ErrInvalidExportFormat = errors.New("invalid export format")
+)
+
+// ExportRecord is the exported representation of a dataset row.
+type ExportRecord struct {
+	L1In            string  `json:"l1_in"`
+	L2In            *string `json:"l2_in"`
+	L3In            *string `json:"l3_in"`
+	L4In            *string `json:"l4_in"`
+	L5In            *string `json:"l5_in"`
+	L6In            *string `json:"l6_in"`
+	L7In            *string `json:"l7_in"`
+	L8In            *string `json:"l8_in"`
+	LevelsIn        *string `json:"levels_in"`
+	InputText       string  `json:"input_text"`
+	FullPathOut     string  `json:"full_path_out"`
+	NameOut         string  `json:"name_out"`
+	CategoryIDOut   int32   `json:"category_id_out"`
+	MatchCategoryID *int32  `json:"match_category_id"`
+	Version         string  `json:"version"`
+	Label           string  `json:"label"`
+}
+
+var exportHeader = []string{
+	"l1_in", "l2_in", "l3_in", "l4_in", "l5_in", "l6_in", "l7_in", "l8_in", "levels_in", "input_text",
+	"full_path_out", "name_out", "category_id_out", "match_category_id", "version", "label",
+}
+
+func newExportRecord(v model.CategoryDataset) ExportRecord {
+	return ExportRecord{
+		L1In:            v.L1In,
+		L2In:            v.L2In,
+		L3In:            v.L3In,
+		L4In:            v.L4In,
+		L5In:            v.L5In,
+		L6In:            v.L6In,
+		L7In:            v.L7In,
+		L8In:            v.L8In,
+		LevelsIn:        v.LevelsIn,
+		InputText:       v.InputText,
+		FullPathOut:     v.FullPathOut,
+		NameOut:         v.NameOut,
+		CategoryIDOut:   v.CategoryIDOut,
+		MatchCategoryID: v.MatchCategoryID,
+		Version:         v.Version,
+		Label:           v.Label,
+	}
+}
+
+func (r ExportRecord) csvRow() []string {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	matchCategoryID := ""
+	if r.MatchCategoryID != nil {
+		matchCategoryID = strconv.Itoa(int(*r.MatchCategoryID))
+	}
+	return []string{
+		r.L1In, deref(r.L2In), deref(r.L3In), deref(r.L4In), deref(r.L5In), deref(r.L6In), deref(r.L7In), deref(r.L8In),
+		deref(r.LevelsIn), r.InputText, r.FullPathOut, r.NameOut, strconv.Itoa(int(r.CategoryIDOut)), matchCategoryID,
+		r.Version, r.Label,
+	}
+}
+
+// ExportDataset writes dataset rows to w as JSON lines or CSV (with a header row).
+func ExportDataset(w io.Writer, rows []model.CategoryDataset, format string) error {
+	ew, err := newExportWriter(w, format)
+	if err != nil {
+		return err
+	}
+	for _, v := range rows {
+		if err = ew.write(v); err != nil {
+			return err
+		}
+	}
+	return ew.flush()
+}
+
+// exportWriter writes dataset rows to an io.Writer one at a time, so that rows can be streamed from the store.
+type exportWriter struct {
+	enc *json.Encoder
+	csv *csv.Writer
+}
+
+// newExportWriter creates an exportWriter for the format, writing the CSV header right away.
+func newExportWriter(w io.Writer, format string) (*exportWriter, error) {
+	switch format {
+	case ExportFormatJSONL:
+		return &exportWriter{enc: json.NewEncoder(w)}, nil
+	case ExportFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportHeader); err != nil {
+			return nil, err
+		}
+		return &exportWriter{csv: cw}, nil
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+}
+
+func (ew *exportWriter) write(v model.CategoryDataset) error {
+	if ew.csv != nil {
+		return ew.csv.Write(newExportRecord(v).csvRow())
+	}
+	return ew.enc.Encode(newExportRecord(v))
+}
+
+func (ew *exportWriter) flush() error {
+	if ew.csv == nil {
+		return nil
+	}
+	ew.csv.Flush()
+	return ew.csv.Error()
+}
@@ -0,0 +1,39 @@
+package transform
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportDataset(t *testing.T) {
+	l2 := "Phones"
+	id := int32(42)
+	rows := []model.CategoryDataset{
+		{
+			L1In: "Shop", L2In: &l2, InputText: "Shop > Phones", FullPathOut: "Electronics > Phones",
+			NameOut: "Phones", CategoryIDOut: 7, MatchCategoryID: &id, Version: "v1", Label: LabelTrain,
+		},
+	}
+
+	var jsonl bytes.Buffer
+	require.NoError(t, ExportDataset(&jsonl, rows, ExportFormatJSONL))
+	assert.JSONEq(t, `{
+		"l1_in":"Shop","l2_in":"Phones","l3_in":null,"l4_in":null,"l5_in":null,"l6_in":null,"l7_in":null,"l8_in":null,
+		"levels_in":null,"input_text":"Shop > Phones","full_path_out":"Electronics > Phones","name_out":"Phones",
+		"category_id_out":7,"match_category_id":42,"version":"v1","label":"train"
+	}`, jsonl.String())
+
+	var csv bytes.Buffer
+	require.NoError(t, ExportDataset(&csv, rows, ExportFormatCSV))
+	assert.Equal(t,
+		"l1_in,l2_in,l3_in,l4_in,l5_in,l6_in,l7_in,l8_in,levels_in,input_text,full_path_out,name_out,category_id_out,match_category_id,version,label\n"+
+			"Shop,Phones,,,,,,,,Shop > Phones,Electronics > Phones,Phones,7,42,v1,train\n",
+		csv.String(),
+	)
+
+	assert.ErrorIs(t, ExportDataset(&bytes.Buffer{}, rows, "xml"), ErrInvalidExportFormat)
+}
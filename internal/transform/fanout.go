@@ -0,0 +1,245 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Partition statuses. A partition stays pending until its job has written all of its rows.
+const (
+	PartitionStatusPending   = "pending"
+	PartitionStatusSucceeded = "succeeded"
+)
+
+var (
+	ErrInvalidFanOut        = errors.New("invalid fan-out")
+	ErrPartitionsNotFound   = errors.New("no partitions found for dataset version")
+	ErrPartitionsIncomplete = errors.New("not all partitions have completed")
+)
+
+// Partition is the slice of match_category IDs in (AfterID, UpToID] generated by one partition job
+// of a fan-out generation (see FanOut).
+type Partition struct {
+	Index   int   `json:"index"`
+	AfterID int32 `json:"after_id"`
+	UpToID  int32 `json:"up_to_id"`
+}
+
+// PartitionInfo is the progress of a partition, as recorded by the CategoryStorer.
+type PartitionInfo struct {
+	Partition
+	Status    string    `json:"status"`
+	Rows      int       `json:"rows"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FanOutInfo is a fan-out generation as recorded by the CategoryStorer: the ID of the request that prepared it
+// (e.g. the SQS message ID), its version, the seed the seeds of its partitions are derived from and its partitions.
+type FanOutInfo struct {
+	ID         string      `json:"id"`
+	Version    string      `json:"version"`
+	Seed       int64       `json:"seed"`
+	Partitions []Partition `json:"partitions"`
+}
+
+// PartitionLockKey is the name under which a partition job locks its partition, so that two runs of the same
+// partition cannot interleave. The partitions of a version share the lock of the version and run in parallel.
+func PartitionLockKey(version string, index int) string {
+	return fmt.Sprintf("%s#partition-%d", version, index)
+}
+
+// partitionRanges splits the IDs in [first, last] into at most n contiguous ranges of about the same width.
+// Without rows (last is 0), a single unbounded partition is returned.
+func partitionRanges(first, last int32, n int) []Partition {
+	if last == 0 || last < first {
+		return []Partition{{}}
+	}
+	width := int64(last) - int64(first) + 1
+	n = int(min(int64(n), width))
+	parts := make([]Partition, 0, n)
+	after := int64(first) - 1
+	for i := range n {
+		upTo := int64(first) - 1 + width*int64(i+1)/int64(n)
+		parts = append(parts, Partition{Index: i, AfterID: int32(after), UpToID: int32(upTo)}) //nolint:gosec // bounded by first and last
+		after = upTo
+	}
+	return parts
+}
+
+// FanOut prepares a generation of the version that is split across several partition jobs.
+// It partitions the matched categories selected by the configuration into at most n contiguous ID ranges,
+// removes the previous rows of the version, stores the current category tree as its taxonomy snapshot,
+// records the partitions as pending under the request id and marks the version as VersionStatusBuilding.
+// It returns one Config per partition; each one is generated with GenerateDataset, independently and in any order.
+// Every partition samples and splits its own rows, with a seed derived from the seed of the run.
+// Once all partitions have completed, Finalize marks the version as ready.
+// A request whose partitions are already recorded (e.g. a redelivered message) gets the configs of those partitions
+// back without touching the version, so partitions that already completed keep their rows.
+// A version reserved by ResolveVersion is released again when FanOut fails.
+// Incremental and resumed runs cannot be fanned out, and neither can options that would apply to every
+// partition on its own instead of the whole version (see Config.validateFanOut).
+func (t *Transform) FanOut(ctx context.Context, id string, n int) (_ []Config, err error) {
+	defer func() {
+		if err != nil {
+			t.releaseVersion()
+		}
+	}()
+	if n < 1 {
+		return nil, fmt.Errorf("%w: partitions must be at least 1", ErrInvalidFanOut)
+	}
+	if t.config.Incremental || t.config.Resume || t.config.Partition != nil {
+		return nil, fmt.Errorf("%w: incremental, resumed and partition runs cannot be fanned out", ErrInvalidFanOut)
+	}
+	if err = t.config.Validate(); err != nil {
+		return nil, err
+	}
+	if err = t.config.validateFanOut(); err != nil {
+		return nil, err
+	}
+	if id != "" {
+		fo, err := t.catStore.FanOutInfo(id)
+		switch {
+		case err == nil:
+			t.config.Version = fo.Version
+			t.log.Info("fan-out already prepared", "version", fo.Version, "fan_out_id", id, "partitions", len(fo.Partitions))
+			return t.partitionConfigs(fo), nil
+		case !errors.Is(err, ErrPartitionsNotFound):
+			return nil, err
+		}
+	}
+	if err = t.ResolveVersion(); err != nil {
+		return nil, err
+	}
+
+	unlock, err := t.lock(ctx, t.config.Version, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if _, _, err = t.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	first, last, err := t.catStore.MatchIDRange(t.config.matchFilter())
+	if err != nil {
+		return nil, err
+	}
+	fo := FanOutInfo{ID: id, Version: t.config.Version, Seed: t.config.Seed, Partitions: partitionRanges(first, last, n)}
+	if fo.Seed == 0 {
+		fo.Seed = t.now().UnixNano()
+	}
+
+	if err = t.catStore.CleanUp(t.config.Version); err != nil {
+		return nil, err
+	}
+	tree, err := t.catStore.CategoryTree()
+	if err != nil {
+		return nil, err
+	}
+	if err = t.catStore.SaveSnapshot(t.config.Version, tree); err != nil {
+		return nil, err
+	}
+	if err = t.catStore.SavePartitions(fo); err != nil {
+		return nil, err
+	}
+	t.log.Info("fanned out generation", "version", t.config.Version, "fan_out_id", id, "partitions", len(fo.Partitions),
+		"first_id", first, "last_id", last, "seed", fo.Seed,
+	)
+	return t.partitionConfigs(fo), nil
+}
+
+// partitionConfigs returns the config of every partition of the fan-out generation.
+func (t *Transform) partitionConfigs(fo FanOutInfo) []Config {
+	configs := make([]Config, 0, len(fo.Partitions))
+	for _, p := range fo.Partitions {
+		cfg := t.config
+		cfg.Version = fo.Version
+		cfg.Seed = fo.Seed + int64(p.Index)
+		cfg.Partition = &p
+		configs = append(configs, cfg)
+	}
+	return configs
+}
+
+// validateFanOut rejects options that cannot be honored when every partition is generated on its own:
+// a Sample.Count and MaxPerCategory would apply to each partition, and StageDedupe would only remove duplicates
+// within a partition. A Sample.Fraction applies to every partition alike and is allowed.
+func (c Config) validateFanOut() error {
+	if c.Sample != nil && c.Sample.Count > 0 {
+		return fmt.Errorf("%w: sample count applies per partition, use a sample fraction instead", ErrInvalidFanOut)
+	}
+	if c.MaxPerCategory > 0 {
+		return fmt.Errorf("%w: max_per_category applies per partition", ErrInvalidFanOut)
+	}
+	if slices.Contains(c.stages(), StageDedupe) {
+		return fmt.Errorf("%w: the dedupe stage cannot remove duplicates across partitions", ErrInvalidFanOut)
+	}
+	return nil
+}
+
+// Finalize completes a fan-out generation of Config.Version once every partition has succeeded:
+// it records the last processed match_category ID, marks the version as active and moves Config.Aliases to it.
+// The taxonomy snapshot is the one stored by FanOut.
+// It returns ErrPartitionsNotFound if the version was not fanned out,
+// and ErrPartitionsIncomplete if a partition is still pending.
+func (t *Transform) Finalize(ctx context.Context) error {
+	tmpl, err := parseVersionTemplate(t.config.Version)
+	if err != nil {
+		return err
+	}
+	if t.config.Version == "" || tmpl.token != "" {
+		return fmt.Errorf("%w: a concrete version is required to finalize", ErrInvalidFanOut)
+	}
+
+	unlock, err := t.lock(ctx, t.config.Version, false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	parts, err := t.catStore.Partitions(t.config.Version)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("%w: %s", ErrPartitionsNotFound, t.config.Version)
+	}
+	done, rows := 0, 0
+	var lastMatchID int32
+	for _, p := range parts {
+		if p.Status == PartitionStatusSucceeded {
+			done++
+		}
+		rows += p.Rows
+		lastMatchID = max(lastMatchID, p.UpToID)
+	}
+	if done < len(parts) {
+		return fmt.Errorf("%w: %d of %d partitions of %s", ErrPartitionsIncomplete, done, len(parts), t.config.Version)
+	}
+
+	if err = t.catStore.SaveVersion(t.config.Version, lastMatchID, t.config.Actor); err != nil {
+		return err
+	}
+	if err = t.catStore.SetVersionStatus(t.config.Version, VersionStatusActive); err != nil {
+		return err
+	}
+	t.log.Info("finalized version", "version", t.config.Version, "partitions", len(parts), "rows", rows)
+
+	if len(t.config.Aliases) == 0 {
+		return nil
+	}
+	if err = t.catStore.MoveAliases(t.config.Version, t.config.Aliases); err != nil {
+		return err
+	}
+	t.log.Info("moved aliases", "aliases", t.config.Aliases, "version", t.config.Version)
+	return nil
+}
+
+// FanInReady reports whether the last GenerateDataset run completed the last pending partition
+// of its version, so that the version can be finalized.
+func (t *Transform) FanInReady() bool {
+	return t.fanInReady
+}
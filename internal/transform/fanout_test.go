@@ -0,0 +1,192 @@
+package transform
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionRanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		first, last int32
+		n           int
+		want        []Partition
+	}{
+		{
+			name: "Even split", first: 1, last: 10, n: 2,
+			want: []Partition{{Index: 0, AfterID: 0, UpToID: 5}, {Index: 1, AfterID: 5, UpToID: 10}},
+		},
+		{
+			name: "Uneven split", first: 11, last: 20, n: 3,
+			want: []Partition{{Index: 0, AfterID: 10, UpToID: 13}, {Index: 1, AfterID: 13, UpToID: 16}, {Index: 2, AfterID: 16, UpToID: 20}},
+		},
+		{
+			name: "More partitions than IDs", first: 4, last: 5, n: 4,
+			want: []Partition{{Index: 0, AfterID: 3, UpToID: 4}, {Index: 1, AfterID: 4, UpToID: 5}},
+		},
+		{
+			name: "No rows", first: 0, last: 0, n: 4,
+			want: []Partition{{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, partitionRanges(tt.first, tt.last, tt.n))
+		})
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().FanOutInfo("msg-1").Return(FanOutInfo{}, ErrPartitionsNotFound)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+	mockStorer.EXPECT().MatchIDRange(MatchFilter{AllowL1: []string{"Shop"}}).Return(int32(1), int32(10), nil)
+	mockStorer.EXPECT().CleanUp("v1").Return(nil)
+	mockStorer.EXPECT().CategoryTree().Return([]CategoryNode{{ID: 7}}, nil)
+	mockStorer.EXPECT().SaveSnapshot("v1", []CategoryNode{{ID: 7}}).Return(nil)
+	parts := []Partition{{Index: 0, AfterID: 0, UpToID: 5}, {Index: 1, AfterID: 5, UpToID: 10}}
+	mockStorer.EXPECT().SavePartitions(FanOutInfo{ID: "msg-1", Version: "v1", Seed: 100, Partitions: parts}).Return(nil)
+
+	cfg := Config{Version: "v1", AllowL1: []string{"Shop"}, Seed: 100, Aliases: []string{AliasLatest}}
+	configs, err := NewTransform(testLogger(), mockStorer, cfg).FanOut(context.Background(), "msg-1", 2)
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	for i, c := range configs {
+		assert.Equal(t, "v1", c.Version)
+		assert.Equal(t, int64(100+i), c.Seed)
+		assert.Equal(t, parts[i], *c.Partition)
+		assert.Equal(t, []string{AliasLatest}, c.Aliases)
+		assert.NoError(t, c.Validate())
+	}
+
+	_, err = NewTransform(testLogger(), NewMockCategoryStorer(t), Config{Version: "v1", Incremental: true}).FanOut(context.Background(), "msg-1", 2)
+	assert.ErrorIs(t, err, ErrInvalidFanOut)
+	_, err = NewTransform(testLogger(), NewMockCategoryStorer(t), Config{Version: "v1"}).FanOut(context.Background(), "msg-1", 0)
+	assert.ErrorIs(t, err, ErrInvalidFanOut)
+}
+
+func TestFanOutRedelivered(t *testing.T) {
+	// The partitions recorded by the first delivery are returned as they are: the version is neither
+	// resolved again nor cleaned up, and completed partitions keep their rows.
+	parts := []Partition{{Index: 0, AfterID: 0, UpToID: 5}, {Index: 1, AfterID: 5, UpToID: 10}}
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().FanOutInfo("msg-1").Return(FanOutInfo{ID: "msg-1", Version: "cat-3", Seed: 100, Partitions: parts}, nil)
+
+	tr := NewTransform(testLogger(), mockStorer, Config{Version: "cat-{counter}"})
+	configs, err := tr.FanOut(context.Background(), "msg-1", 4)
+	require.NoError(t, err)
+	assert.Equal(t, "cat-3", tr.Version())
+	require.Len(t, configs, 2)
+	for i, c := range configs {
+		assert.Equal(t, "cat-3", c.Version)
+		assert.Equal(t, int64(100+i), c.Seed)
+		assert.Equal(t, parts[i], *c.Partition)
+	}
+}
+
+func TestFanOutPerPartitionOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "Sample count", cfg: Config{Version: "v1", Sample: &SampleConfig{Count: 100}}, wantErr: true},
+		{name: "Max per category", cfg: Config{Version: "v1", MaxPerCategory: 10}, wantErr: true},
+		{name: "Dedupe stage", cfg: Config{Version: "v1", Stages: []string{StageDedupe}}, wantErr: true},
+		{name: "Sample fraction", cfg: Config{Version: "v1", Sample: &SampleConfig{Fraction: 0.5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validateFanOut()
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidFanOut)
+			_, err = NewTransform(testLogger(), NewMockCategoryStorer(t), tt.cfg).FanOut(context.Background(), "msg-1", 2)
+			assert.ErrorIs(t, err, ErrInvalidFanOut)
+
+			partition := tt.cfg
+			partition.Partition = &Partition{Index: 0}
+			assert.ErrorIs(t, partition.Validate(), ErrInvalidFanOut)
+		})
+	}
+}
+
+func TestGenerateDatasetPartition(t *testing.T) {
+	tests := []struct {
+		name      string
+		pending   int
+		wantReady bool
+	}{
+		{name: "Partitions pending", pending: 1, wantReady: false},
+		{name: "Last partition", pending: 0, wantReady: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Partition{Index: 1, AfterID: 5, UpToID: 10}
+			mockStorer := NewMockCategoryStorer(t)
+			mockStorer.EXPECT().LockVersion(mock.Anything, "v1", true, time.Duration(0)).Return(unlockNop, nil)
+			mockStorer.EXPECT().LockVersion(mock.Anything, "v1#partition-1", false, time.Duration(0)).Return(unlockNop, nil)
+			mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1", Status: VersionStatusBuilding}, nil)
+			mockStorer.EXPECT().OriginalCategory().Return(Category{7: {Name: "Cat7", Path: "/cat7"}}, nil)
+			mockStorer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+			mockStorer.EXPECT().MatchedCategory(MatchFilter{AfterID: 5, UpToID: 10}).Return([]model.MatchCategory{
+				{ID: 6, L1: "Shop", MatchID: matchID(7)},
+			}, nil)
+			mockStorer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+			mockStorer.EXPECT().CleanUpPartition("v1", p).Return(nil)
+			mockStorer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+			mockStorer.EXPECT().FinishPartition("v1", 1, 1).Return(tt.pending, nil)
+
+			tr := NewTransform(testLogger(), mockStorer, Config{
+				Version: "v1", TrainRatio: 100, Partition: &p, Aliases: []string{AliasLatest},
+			})
+			_, err := tr.GenerateDataset(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReady, tr.FanInReady())
+		})
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	done := PartitionInfo{Partition: Partition{Index: 0, UpToID: 5}, Status: PartitionStatusSucceeded, Rows: 3}
+
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+	mockStorer.EXPECT().Partitions("v1").Return([]PartitionInfo{
+		done, {Partition: Partition{Index: 1, AfterID: 5, UpToID: 10}, Status: PartitionStatusPending},
+	}, nil)
+	err := NewTransform(testLogger(), mockStorer, Config{Version: "v1"}).Finalize(context.Background())
+	assert.ErrorIs(t, err, ErrPartitionsIncomplete)
+
+	mockStorer = NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+	mockStorer.EXPECT().Partitions("v1").Return(nil, nil)
+	err = NewTransform(testLogger(), mockStorer, Config{Version: "v1"}).Finalize(context.Background())
+	assert.ErrorIs(t, err, ErrPartitionsNotFound)
+
+	mockStorer = NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+	mockStorer.EXPECT().Partitions("v1").Return([]PartitionInfo{
+		done, {Partition: Partition{Index: 1, AfterID: 5, UpToID: 10}, Status: PartitionStatusSucceeded, Rows: 4},
+	}, nil)
+	mockStorer.EXPECT().SaveVersion("v1", int32(10), "alice").Return(nil)
+	mockStorer.EXPECT().SetVersionStatus("v1", VersionStatusActive).Return(nil)
+	mockStorer.EXPECT().MoveAliases("v1", []string{AliasLatest}).Return(nil)
+	err = NewTransform(testLogger(), mockStorer, Config{Version: "v1", Aliases: []string{AliasLatest}, Actor: "alice"}).Finalize(context.Background())
+	assert.NoError(t, err)
+
+	err = NewTransform(testLogger(), NewMockCategoryStorer(t), Config{Version: "v{counter}"}).Finalize(context.Background())
+	assert.ErrorIs(t, err, ErrInvalidFanOut)
+}
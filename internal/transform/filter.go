@@ -0,0 +1,71 @@
+package transform
+
+// MatchFilter restricts which matched categories are read from the CategoryStorer.
+// Empty lists do not filter. AllowL1 keeps only rows whose L1 is in the list and DenyL1 removes
+// rows whose L1 is in the list; both are applied by the store query.
+// AfterID, when non-zero, only keeps rows with a higher ID and is used by incremental runs.
+// UpToID, when non-zero, only keeps rows with an ID up to and including it and is used by resumed runs.
+type MatchFilter struct {
+	AllowL1 []string
+	DenyL1  []string
+	AfterID int32
+	UpToID  int32
+}
+
+func (c Config) matchFilter() MatchFilter {
+	return MatchFilter{
+		AllowL1: c.AllowL1,
+		DenyL1:  c.DenyL1,
+	}
+}
+
+// subtree returns the IDs of the given root categories and all of their descendants.
+func subtree(tree []CategoryNode, roots []int32) map[int32]struct{} {
+	children := make(map[int32][]int32)
+	for _, n := range tree {
+		if n.ParentID != nil {
+			children[*n.ParentID] = append(children[*n.ParentID], n.ID)
+		}
+	}
+
+	ids := make(map[int32]struct{})
+	queue := append([]int32(nil), roots...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := ids[id]; ok {
+			continue
+		}
+		ids[id] = struct{}{}
+		queue = append(queue, children[id]...)
+	}
+	return ids
+}
+
+// categoryFilter keeps categories that are inside the included subtrees (when any are configured)
+// and outside the excluded subtrees.
+type categoryFilter struct {
+	include map[int32]struct{}
+	exclude map[int32]struct{}
+}
+
+func newCategoryFilter(tree []CategoryNode, include, exclude []int32) categoryFilter {
+	var f categoryFilter
+	if len(include) > 0 {
+		f.include = subtree(tree, include)
+	}
+	if len(exclude) > 0 {
+		f.exclude = subtree(tree, exclude)
+	}
+	return f
+}
+
+func (f categoryFilter) keep(id int32) bool {
+	if f.include != nil {
+		if _, ok := f.include[id]; !ok {
+			return false
+		}
+	}
+	_, excluded := f.exclude[id]
+	return !excluded
+}
@@ -0,0 +1,46 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryFilter(t *testing.T) {
+	parent := func(i int32) *int32 { return &i }
+	tree := []CategoryNode{
+		{ID: 1, Name: "Electronics"},
+		{ID: 2, Name: "Phones", ParentID: parent(1)},
+		{ID: 3, Name: "Android", ParentID: parent(2)},
+		{ID: 4, Name: "Laptops", ParentID: parent(1)},
+		{ID: 5, Name: "Home"},
+		{ID: 6, Name: "Lamps", ParentID: parent(5)},
+	}
+
+	assert.Equal(t, map[int32]struct{}{2: {}, 3: {}}, subtree(tree, []int32{2}))
+
+	tests := []struct {
+		name    string
+		include []int32
+		exclude []int32
+		want    []int32
+	}{
+		{name: "No filter", want: []int32{1, 2, 3, 4, 5, 6}},
+		{name: "Include subtree", include: []int32{1}, want: []int32{1, 2, 3, 4}},
+		{name: "Exclude subtree", exclude: []int32{2}, want: []int32{1, 4, 5, 6}},
+		{name: "Include and exclude", include: []int32{1}, exclude: []int32{2}, want: []int32{1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCategoryFilter(tree, tt.include, tt.exclude)
+			var got []int32
+			for _, n := range tree {
+				if f.keep(n.ID) {
+					got = append(got, n.ID)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,385 @@
+package transform
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+)
+
+var (
+	ErrInvalidRetention = errors.New("invalid retention policy: set keep_last and/or older_than")
+	ErrNoArchiveDir     = errors.New("no archive directory configured, refusing to archive or export")
+	ErrVersionAliased   = errors.New("dataset version is pointed to by an alias, set force to remove it")
+	ErrVersionArchived  = errors.New("dataset version is already archived")
+	ErrArchiveExists    = errors.New("archive file already exists")
+)
+
+// VersionStorer defines the storage operations needed to manage the lifecycle of dataset versions.
+type VersionStorer interface {
+	ListVersions() ([]VersionSummary, error)
+	VersionInfo(version string) (VersionInfo, error)
+	SetVersionStatus(version, status string) error
+	SetVersionImmutable(version string, immutable bool) error
+	Dataset(version string) ([]model.CategoryDataset, error)
+	EachDatasetRow(ctx context.Context, version, label string, fn func(model.CategoryDataset) error) error
+	CleanUp(version string) error
+	DeleteVersion(version string) error
+	ListAliases() ([]Alias, error)
+	LockVersion(ctx context.Context, version string, shared bool, wait time.Duration) (func() error, error)
+}
+
+// RetentionPolicy selects old versions for clean-up. A version is selected when it is not among the
+// KeepLast most recently created versions and, when OlderThanDays is set, was created more than that many days ago.
+// Action is what happens to selected versions: VersionStatusRetired, VersionStatusArchived or "delete" (default).
+// Immutable versions, versions an alias points to and versions that are still being built are never selected.
+type RetentionPolicy struct {
+	KeepLast      int    `json:"keep_last"`
+	OlderThanDays int    `json:"older_than_days"`
+	Action        string `json:"action"`
+}
+
+// RetentionActionDelete deletes versions selected by a RetentionPolicy.
+const RetentionActionDelete = "delete"
+
+// Lifecycle lists, protects, retires, archives and deletes dataset versions.
+type Lifecycle struct {
+	log        *slog.Logger
+	store      VersionStorer
+	archiveDir string
+	now        func() time.Time
+}
+
+// NewLifecycle creates a new Lifecycle. Archives and exports are written to archiveDir, which must be durable
+// storage (e.g. an EFS mount in Lambda) because archiving removes the rows from the database.
+// When archiveDir is empty, Archive and Export fail with ErrNoArchiveDir.
+func NewLifecycle(l *slog.Logger, vs VersionStorer, archiveDir string) *Lifecycle {
+	return &Lifecycle{
+		log:        l.With("component", "lifecycle"),
+		store:      vs,
+		archiveDir: archiveDir,
+		now:        time.Now,
+	}
+}
+
+// List returns every dataset version with its row counts, newest first.
+// Versions that are still being built are left out.
+func (lc *Lifecycle) List() ([]VersionSummary, error) {
+	versions, err := lc.store.ListVersions()
+	if err != nil {
+		return nil, err
+	}
+	versions = slices.DeleteFunc(versions, func(v VersionSummary) bool { return v.Status == VersionStatusBuilding })
+	slices.SortFunc(versions, func(a, b VersionSummary) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return versions, nil
+}
+
+// Protect marks a version as immutable so that GenerateDataset refuses to overwrite it without Config.Force.
+func (lc *Lifecycle) Protect(version string) error {
+	if err := lc.store.SetVersionImmutable(version, true); err != nil {
+		return err
+	}
+	lc.log.Info("protected version", "version", version)
+	return nil
+}
+
+// Unprotect makes an immutable version writable again.
+func (lc *Lifecycle) Unprotect(version string) error {
+	if err := lc.store.SetVersionImmutable(version, false); err != nil {
+		return err
+	}
+	lc.log.Info("unprotected version", "version", version)
+	return nil
+}
+
+// Retire marks a version as retired. Its rows are kept.
+func (lc *Lifecycle) Retire(version string) error {
+	if err := lc.store.SetVersionStatus(version, VersionStatusRetired); err != nil {
+		return err
+	}
+	lc.log.Info("retired version", "version", version)
+	return nil
+}
+
+// Archive exports the rows of a version as JSON lines to <archiveDir>/<version>.jsonl,
+// removes the rows from the dataset table and marks the version as archived.
+// The archive is written to a temporary file which is only moved into place once synced to disk,
+// and the rows are only removed after that. An existing archive file is never overwritten.
+// It returns ErrNoArchiveDir without touching the version when no archive directory is configured,
+// ErrVersionLocked while the version is being generated,
+// ErrVersionArchived or ErrArchiveExists when the version has already been archived,
+// ErrVersionImmutable or ErrVersionAliased when the version is immutable or pointed to by an alias and force
+// is not set, and otherwise the path of the archive file.
+func (lc *Lifecycle) Archive(version string, force bool) (string, error) {
+	if lc.archiveDir == "" {
+		return "", ErrNoArchiveDir
+	}
+	unlock, err := lc.lock(version)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	info, err := lc.checkRemovable(version, force)
+	if err != nil {
+		return "", err
+	}
+	if info.Status == VersionStatusArchived {
+		return "", fmt.Errorf("%w: %s", ErrVersionArchived, version)
+	}
+	path := filepath.Join(lc.archiveDir, filepath.Base(version)+".jsonl")
+	if _, err = os.Stat(path); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrArchiveExists, path)
+	}
+	rows, err := lc.store.Dataset(version)
+	if err != nil {
+		return "", err
+	}
+	if err = writeArchive(path, rows); err != nil {
+		return "", err
+	}
+
+	if err = lc.store.CleanUp(version); err != nil {
+		return "", err
+	}
+	if err = lc.store.SetVersionStatus(version, VersionStatusArchived); err != nil {
+		return "", err
+	}
+	lc.log.Info("archived version", "version", version, "rows", len(rows), "path", path)
+	return path, nil
+}
+
+// writeArchive writes the rows as JSON lines to a temporary file next to path and links it to path once synced,
+// which fails with ErrArchiveExists instead of replacing a file created in the meantime.
+func writeArchive(path string, rows []model.CategoryDataset) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err = ExportDataset(f, rows, ExportFormatJSONL); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	if err = os.Link(f.Name(), path); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrArchiveExists, path)
+		}
+		return err
+	}
+	return nil
+}
+
+// Export writes the rows of a version, or only those with the given split label when label is not empty,
+// to <archiveDir>/<version>.<format> (<archiveDir>/<version>-<label>.<format> for a single split).
+// The rows are kept. It returns the path of the export file, or ErrNoArchiveDir when no archive directory is configured.
+func (lc *Lifecycle) Export(version, format, label string) (string, error) {
+	if lc.archiveDir == "" {
+		return "", ErrNoArchiveDir
+	}
+	switch format {
+	case ExportFormatJSONL, ExportFormatCSV:
+	default:
+		return "", ErrInvalidExportFormat
+	}
+	name := filepath.Base(version)
+	if label != "" {
+		name += "-" + filepath.Base(label)
+	}
+
+	path := filepath.Join(lc.archiveDir, name+"."+format)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	rows, err := lc.WriteExport(context.Background(), f, version, format, label)
+	if err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err = f.Close(); err != nil {
+		return "", err
+	}
+	lc.log.Info("exported version", "version", version, "label", label, "rows", rows, "path", path)
+	return path, nil
+}
+
+// WriteExport streams the rows of a version, or only those with the given split label when label is not empty,
+// to w in the given format. It returns the number of rows written.
+func (lc *Lifecycle) WriteExport(ctx context.Context, w io.Writer, version, format, label string) (int, error) {
+	ew, err := newExportWriter(w, format)
+	if err != nil {
+		return 0, err
+	}
+	rows := 0
+	err = lc.store.EachDatasetRow(ctx, version, label, func(v model.CategoryDataset) error {
+		rows++
+		return ew.write(v)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = ew.flush(); err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// Stats returns the row counts of a single version, or ErrVersionNotFound.
+func (lc *Lifecycle) Stats(version string) (VersionSummary, error) {
+	versions, err := lc.store.ListVersions()
+	if err != nil {
+		return VersionSummary{}, err
+	}
+	i := slices.IndexFunc(versions, func(v VersionSummary) bool { return v.Version == version })
+	if i < 0 {
+		return VersionSummary{}, ErrVersionNotFound
+	}
+	return versions[i], nil
+}
+
+// Delete removes a version, its rows, its taxonomy snapshot and, when forced, the aliases pointing to it.
+// It returns ErrVersionNotFound for unknown versions, ErrVersionLocked while the version is being generated,
+// and ErrVersionImmutable or ErrVersionAliased when the version is immutable or pointed to by an alias
+// and force is not set.
+func (lc *Lifecycle) Delete(version string, force bool) error {
+	unlock, err := lc.lock(version)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if _, err = lc.checkRemovable(version, force); err != nil {
+		return err
+	}
+	if err := lc.store.DeleteVersion(version); err != nil {
+		return err
+	}
+	lc.log.Info("deleted version", "version", version)
+	return nil
+}
+
+// lock takes the exclusive lock of the version without waiting, so that a version is never removed
+// while it is being generated. The returned function releases it.
+func (lc *Lifecycle) lock(version string) (func(), error) {
+	unlock, err := lc.store.LockVersion(context.Background(), version, false, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, version)
+	}
+	return func() {
+		if uErr := unlock(); uErr != nil {
+			lc.log.Warn("failed to unlock version", "version", version, "error", uErr)
+		}
+	}, nil
+}
+
+// checkRemovable returns the VersionInfo of the version, or ErrVersionImmutable or ErrVersionAliased
+// if the rows of the version may not be removed. force overrides the checks.
+func (lc *Lifecycle) checkRemovable(version string, force bool) (VersionInfo, error) {
+	info, err := lc.store.VersionInfo(version)
+	if err != nil {
+		return VersionInfo{}, err
+	}
+	aliased, err := lc.aliasedVersions()
+	if err != nil {
+		return VersionInfo{}, err
+	}
+	names := aliased[version]
+	switch {
+	case !info.Immutable && len(names) == 0:
+		return info, nil
+	case !force && info.Immutable:
+		return VersionInfo{}, fmt.Errorf("%w: %s", ErrVersionImmutable, version)
+	case !force:
+		return VersionInfo{}, fmt.Errorf("%w: %s is aliased as %v", ErrVersionAliased, version, names)
+	}
+	lc.log.Warn("removing protected version", "version", version, "immutable", info.Immutable, "aliases", names)
+	return info, nil
+}
+
+// aliasedVersions returns the names of the aliases pointing to each version.
+func (lc *Lifecycle) aliasedVersions() (map[string][]string, error) {
+	aliases, err := lc.store.ListAliases()
+	if err != nil {
+		return nil, err
+	}
+	aliased := make(map[string][]string, len(aliases))
+	for _, a := range aliases {
+		aliased[a.Version] = append(aliased[a.Version], a.Name)
+	}
+	return aliased, nil
+}
+
+// ApplyRetention applies the policy to all versions and returns the versions it acted on.
+func (lc *Lifecycle) ApplyRetention(policy RetentionPolicy) ([]string, error) {
+	if policy.KeepLast <= 0 && policy.OlderThanDays <= 0 {
+		return nil, ErrInvalidRetention
+	}
+	action := cmp.Or(policy.Action, RetentionActionDelete)
+	switch action {
+	case VersionStatusRetired, VersionStatusArchived, RetentionActionDelete:
+	default:
+		return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidRetention, action)
+	}
+	if action == VersionStatusArchived && lc.archiveDir == "" {
+		return nil, ErrNoArchiveDir
+	}
+
+	versions, err := lc.List()
+	if err != nil {
+		return nil, err
+	}
+	aliased, err := lc.aliasedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := lc.now().AddDate(0, 0, -policy.OlderThanDays)
+	var selected []string
+	for i, v := range versions {
+		if policy.KeepLast > 0 && i < policy.KeepLast {
+			continue
+		}
+		if policy.OlderThanDays > 0 && !v.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if v.Immutable || len(aliased[v.Version]) > 0 {
+			continue
+		}
+		if v.Status == action || (action == VersionStatusRetired && v.Status == VersionStatusArchived) {
+			continue
+		}
+		switch action {
+		case VersionStatusRetired:
+			err = lc.Retire(v.Version)
+		case VersionStatusArchived:
+			_, err = lc.Archive(v.Version, false)
+		default:
+			err = lc.Delete(v.Version, false)
+		}
+		if err != nil {
+			return selected, err
+		}
+		selected = append(selected, v.Version)
+	}
+	lc.log.Info("applied retention policy",
+		"keep_last", policy.KeepLast, "older_than_days", policy.OlderThanDays, "action", action, "versions", selected,
+	)
+	return selected, nil
+}
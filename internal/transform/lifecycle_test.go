@@ -0,0 +1,291 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectLock lets the Lifecycle lock any version it archives or deletes.
+func expectLock(storer *MockVersionStorer) {
+	storer.EXPECT().LockVersion(mock.Anything, mock.Anything, false, time.Duration(0)).Return(unlockNop, nil).Maybe()
+}
+
+func TestLifecycleArchive(t *testing.T) {
+	dir := t.TempDir()
+	mockStorer := NewMockVersionStorer(t)
+	expectLock(mockStorer)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1"}, nil)
+	mockStorer.EXPECT().ListAliases().Return(nil, nil)
+	mockStorer.EXPECT().Dataset("v1").Return([]model.CategoryDataset{
+		{L1In: "Shop", NameOut: "Phones", Version: "v1", Label: LabelTrain},
+	}, nil)
+	mockStorer.EXPECT().CleanUp("v1").Return(nil)
+	mockStorer.EXPECT().SetVersionStatus("v1", VersionStatusArchived).Return(nil)
+
+	path, err := NewLifecycle(testLogger(), mockStorer, dir).Archive("v1", false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "v1.jsonl"), path)
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"name_out":"Phones"`)
+}
+
+func TestLifecycleArchiveTwice(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "v2.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0o600))
+
+	mockStorer := NewMockVersionStorer(t)
+	expectLock(mockStorer)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1", Status: VersionStatusArchived}, nil)
+	mockStorer.EXPECT().VersionInfo("v2").Return(VersionInfo{Version: "v2", Status: VersionStatusActive}, nil)
+	mockStorer.EXPECT().ListAliases().Return(nil, nil)
+	lc := NewLifecycle(testLogger(), mockStorer, dir)
+
+	_, err := lc.Archive("v1", false)
+	assert.ErrorIs(t, err, ErrVersionArchived)
+	_, err = lc.Archive("v2", false)
+	assert.ErrorIs(t, err, ErrArchiveExists)
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{}\n", string(b))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestLifecycleArchiveWithoutDir(t *testing.T) {
+	// The rows must not be read, let alone removed.
+	_, err := NewLifecycle(testLogger(), NewMockVersionStorer(t), "").Archive("v1", false)
+	assert.ErrorIs(t, err, ErrNoArchiveDir)
+
+	_, err = NewLifecycle(testLogger(), NewMockVersionStorer(t), "").ApplyRetention(
+		RetentionPolicy{KeepLast: 1, Action: VersionStatusArchived},
+	)
+	assert.ErrorIs(t, err, ErrNoArchiveDir)
+
+	_, err = NewLifecycle(testLogger(), NewMockVersionStorer(t), "").Export("v1", ExportFormatJSONL, "")
+	assert.ErrorIs(t, err, ErrNoArchiveDir)
+}
+
+func TestLifecycleImmutable(t *testing.T) {
+	mockStorer := NewMockVersionStorer(t)
+	expectLock(mockStorer)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1", Immutable: true}, nil)
+	mockStorer.EXPECT().ListAliases().Return(nil, nil)
+	lc := NewLifecycle(testLogger(), mockStorer, t.TempDir())
+
+	assert.ErrorIs(t, lc.Delete("v1", false), ErrVersionImmutable)
+	_, err := lc.Archive("v1", false)
+	assert.ErrorIs(t, err, ErrVersionImmutable)
+
+	mockStorer.EXPECT().DeleteVersion("v1").Return(nil)
+	assert.NoError(t, lc.Delete("v1", true))
+
+	mockStorer = NewMockVersionStorer(t)
+	expectLock(mockStorer)
+	mockStorer.EXPECT().VersionInfo("v2").Return(VersionInfo{}, ErrVersionNotFound)
+	assert.ErrorIs(t, NewLifecycle(testLogger(), mockStorer, "").Delete("v2", false), ErrVersionNotFound)
+}
+
+func TestLifecycleAliased(t *testing.T) {
+	mockStorer := NewMockVersionStorer(t)
+	expectLock(mockStorer)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1"}, nil)
+	mockStorer.EXPECT().ListAliases().Return([]Alias{{Name: AliasProduction, Version: "v1"}, {Name: AliasLatest, Version: "v2"}}, nil)
+	lc := NewLifecycle(testLogger(), mockStorer, t.TempDir())
+
+	assert.ErrorIs(t, lc.Delete("v1", false), ErrVersionAliased)
+	_, err := lc.Archive("v1", false)
+	assert.ErrorIs(t, err, ErrVersionAliased)
+
+	mockStorer.EXPECT().DeleteVersion("v1").Return(nil)
+	assert.NoError(t, lc.Delete("v1", true))
+}
+
+func TestLifecycleLocked(t *testing.T) {
+	// A version that is being generated is neither read nor removed.
+	mockStorer := NewMockVersionStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(nil, ErrVersionLocked)
+	lc := NewLifecycle(testLogger(), mockStorer, t.TempDir())
+
+	assert.ErrorIs(t, lc.Delete("v1", true), ErrVersionLocked)
+	_, err := lc.Archive("v1", true)
+	assert.ErrorIs(t, err, ErrVersionLocked)
+}
+
+func TestLifecycleArchiveFailedDataset(t *testing.T) {
+	mockStorer := NewMockVersionStorer(t)
+	expectLock(mockStorer)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1"}, nil)
+	mockStorer.EXPECT().ListAliases().Return(nil, nil)
+	mockStorer.EXPECT().Dataset("v1").Return(nil, errors.New("dataset error"))
+
+	_, err := NewLifecycle(testLogger(), mockStorer, t.TempDir()).Archive("v1", false)
+	assert.EqualError(t, err, "dataset error")
+}
+
+func TestLifecycleExport(t *testing.T) {
+	dir := t.TempDir()
+	mockStorer := NewMockVersionStorer(t)
+	expectLock(mockStorer)
+	// The store only reads the rows of the label.
+	mockStorer.EXPECT().EachDatasetRow(mock.Anything, "v1", LabelTest, mock.Anything).RunAndReturn(
+		func(_ context.Context, _, _ string, fn func(model.CategoryDataset) error) error {
+			return fn(model.CategoryDataset{L1In: "Shop", NameOut: "Laptops", Version: "v1", Label: LabelTest})
+		},
+	)
+
+	path, err := NewLifecycle(testLogger(), mockStorer, dir).Export("v1", ExportFormatCSV, LabelTest)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "v1-test.csv"), path)
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(b), "\n"))
+	assert.Contains(t, string(b), "Laptops")
+
+	_, err = NewLifecycle(testLogger(), NewMockVersionStorer(t), dir).Export("v1", "xml", "")
+	assert.ErrorIs(t, err, ErrInvalidExportFormat)
+}
+
+func TestLifecycleStats(t *testing.T) {
+	mockStorer := NewMockVersionStorer(t)
+	expectLock(mockStorer)
+	mockStorer.EXPECT().ListVersions().Return([]VersionSummary{
+		{VersionInfo: VersionInfo{Version: "v1"}, Rows: 3},
+		{VersionInfo: VersionInfo{Version: "v2"}, Rows: 5},
+	}, nil)
+
+	lc := NewLifecycle(testLogger(), mockStorer, t.TempDir())
+	stats, err := lc.Stats("v2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stats.Rows)
+
+	_, err = lc.Stats("v3")
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+}
+
+func TestLifecycleApplyRetention(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	versions := func() []VersionSummary {
+		return []VersionSummary{
+			{VersionInfo: VersionInfo{Version: "v1", Status: VersionStatusActive, CreatedAt: now.AddDate(0, 0, -60)}},
+			{VersionInfo: VersionInfo{Version: "v4", Status: VersionStatusActive, CreatedAt: now.AddDate(0, 0, -1)}},
+			{VersionInfo: VersionInfo{Version: "v2", Status: VersionStatusRetired, CreatedAt: now.AddDate(0, 0, -40)}},
+			{VersionInfo: VersionInfo{Version: "v3", Status: VersionStatusActive, CreatedAt: now.AddDate(0, 0, -10)}},
+			{VersionInfo: VersionInfo{Version: "v5", Status: VersionStatusBuilding, CreatedAt: now.AddDate(0, 0, -90)}},
+		}
+	}
+
+	type mockBehavior func(storer *MockVersionStorer)
+	tests := []struct {
+		name         string
+		policy       RetentionPolicy
+		mockBehavior mockBehavior
+		want         []string
+		wantErr      error
+	}{
+		{
+			name:   "Keep last deletes the rest",
+			policy: RetentionPolicy{KeepLast: 2},
+			mockBehavior: func(storer *MockVersionStorer) {
+				storer.EXPECT().ListVersions().Return(versions(), nil)
+				storer.EXPECT().ListAliases().Return(nil, nil)
+				storer.EXPECT().VersionInfo("v2").Return(VersionInfo{Version: "v2"}, nil)
+				storer.EXPECT().DeleteVersion("v2").Return(nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1"}, nil)
+				storer.EXPECT().DeleteVersion("v1").Return(nil)
+			},
+			want: []string{"v2", "v1"},
+		},
+		{
+			name:   "Older than retires",
+			policy: RetentionPolicy{OlderThanDays: 30, Action: VersionStatusRetired},
+			mockBehavior: func(storer *MockVersionStorer) {
+				storer.EXPECT().ListVersions().Return(versions(), nil)
+				storer.EXPECT().ListAliases().Return(nil, nil)
+				storer.EXPECT().SetVersionStatus("v1", VersionStatusRetired).Return(nil)
+			},
+			want: []string{"v1"},
+		},
+		{
+			name:   "Keep last and older than",
+			policy: RetentionPolicy{KeepLast: 1, OlderThanDays: 50},
+			mockBehavior: func(storer *MockVersionStorer) {
+				storer.EXPECT().ListVersions().Return(versions(), nil)
+				storer.EXPECT().ListAliases().Return(nil, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1"}, nil)
+				storer.EXPECT().DeleteVersion("v1").Return(nil)
+			},
+			want: []string{"v1"},
+		},
+		{
+			name:   "Immutable versions are kept",
+			policy: RetentionPolicy{KeepLast: 2},
+			mockBehavior: func(storer *MockVersionStorer) {
+				vs := versions()
+				vs[0].Immutable = true
+				storer.EXPECT().ListVersions().Return(vs, nil)
+				storer.EXPECT().ListAliases().Return(nil, nil)
+				storer.EXPECT().VersionInfo("v2").Return(VersionInfo{Version: "v2"}, nil)
+				storer.EXPECT().DeleteVersion("v2").Return(nil)
+			},
+			want: []string{"v2"},
+		},
+		{
+			name:   "Aliased versions are kept",
+			policy: RetentionPolicy{KeepLast: 2},
+			mockBehavior: func(storer *MockVersionStorer) {
+				storer.EXPECT().ListVersions().Return(versions(), nil)
+				storer.EXPECT().ListAliases().Return([]Alias{{Name: AliasProduction, Version: "v1"}}, nil)
+				storer.EXPECT().VersionInfo("v2").Return(VersionInfo{Version: "v2"}, nil)
+				storer.EXPECT().DeleteVersion("v2").Return(nil)
+			},
+			want: []string{"v2"},
+		},
+		{
+			name:         "Empty policy",
+			policy:       RetentionPolicy{},
+			mockBehavior: func(storer *MockVersionStorer) {},
+			wantErr:      ErrInvalidRetention,
+		},
+		{
+			name:         "Unknown action",
+			policy:       RetentionPolicy{KeepLast: 1, Action: "shred"},
+			mockBehavior: func(storer *MockVersionStorer) {},
+			wantErr:      ErrInvalidRetention,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorer := NewMockVersionStorer(t)
+			expectLock(mockStorer)
+			tt.mockBehavior(mockStorer)
+
+			lc := NewLifecycle(testLogger(), mockStorer, t.TempDir())
+			lc.now = func() time.Time { return now }
+			got, err := lc.ApplyRetention(tt.policy)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,8 +3,10 @@
 package transform
 
 import (
+	context "context"
 	model "github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
 	mock "github.com/stretchr/testify/mock"
+	time "time"
 )
 
 // MockCategoryStorer is an autogenerated mock type for the CategoryStorer type
@@ -20,6 +22,176 @@ func (_m *MockCategoryStorer) EXPECT() *MockCategoryStorer_Expecter {
 	return &MockCategoryStorer_Expecter{mock: &_m.Mock}
 }
 
+// CategoryRemap provides a mock function with given fields:
+func (_m *MockCategoryStorer) CategoryRemap() (Remap, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CategoryRemap")
+	}
+
+	var r0 Remap
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (Remap, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() Remap); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Remap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_CategoryRemap_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CategoryRemap'
+type MockCategoryStorer_CategoryRemap_Call struct {
+	*mock.Call
+}
+
+// CategoryRemap is a helper method to define mock.On call
+func (_e *MockCategoryStorer_Expecter) CategoryRemap() *MockCategoryStorer_CategoryRemap_Call {
+	return &MockCategoryStorer_CategoryRemap_Call{Call: _e.mock.On("CategoryRemap")}
+}
+
+func (_c *MockCategoryStorer_CategoryRemap_Call) Run(run func()) *MockCategoryStorer_CategoryRemap_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_CategoryRemap_Call) Return(_a0 Remap, _a1 error) *MockCategoryStorer_CategoryRemap_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_CategoryRemap_Call) RunAndReturn(run func() (Remap, error)) *MockCategoryStorer_CategoryRemap_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CategoryTree provides a mock function with given fields:
+func (_m *MockCategoryStorer) CategoryTree() ([]CategoryNode, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CategoryTree")
+	}
+
+	var r0 []CategoryNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]CategoryNode, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []CategoryNode); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]CategoryNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_CategoryTree_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CategoryTree'
+type MockCategoryStorer_CategoryTree_Call struct {
+	*mock.Call
+}
+
+// CategoryTree is a helper method to define mock.On call
+func (_e *MockCategoryStorer_Expecter) CategoryTree() *MockCategoryStorer_CategoryTree_Call {
+	return &MockCategoryStorer_CategoryTree_Call{Call: _e.mock.On("CategoryTree")}
+}
+
+func (_c *MockCategoryStorer_CategoryTree_Call) Run(run func()) *MockCategoryStorer_CategoryTree_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_CategoryTree_Call) Return(_a0 []CategoryNode, _a1 error) *MockCategoryStorer_CategoryTree_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_CategoryTree_Call) RunAndReturn(run func() ([]CategoryNode, error)) *MockCategoryStorer_CategoryTree_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Checkpoint provides a mock function with given fields: version
+func (_m *MockCategoryStorer) Checkpoint(version string) (Checkpoint, error) {
+	ret := _m.Called(version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Checkpoint")
+	}
+
+	var r0 Checkpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (Checkpoint, error)); ok {
+		return rf(version)
+	}
+	if rf, ok := ret.Get(0).(func(string) Checkpoint); ok {
+		r0 = rf(version)
+	} else {
+		r0 = ret.Get(0).(Checkpoint)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_Checkpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Checkpoint'
+type MockCategoryStorer_Checkpoint_Call struct {
+	*mock.Call
+}
+
+// Checkpoint is a helper method to define mock.On call
+//   - version string
+func (_e *MockCategoryStorer_Expecter) Checkpoint(version interface{}) *MockCategoryStorer_Checkpoint_Call {
+	return &MockCategoryStorer_Checkpoint_Call{Call: _e.mock.On("Checkpoint", version)}
+}
+
+func (_c *MockCategoryStorer_Checkpoint_Call) Run(run func(version string)) *MockCategoryStorer_Checkpoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_Checkpoint_Call) Return(_a0 Checkpoint, _a1 error) *MockCategoryStorer_Checkpoint_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_Checkpoint_Call) RunAndReturn(run func(string) (Checkpoint, error)) *MockCategoryStorer_Checkpoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CleanUp provides a mock function with given fields: version
 func (_m *MockCategoryStorer) CleanUp(version string) error {
 	ret := _m.Called(version)
@@ -66,17 +238,17 @@ func (_c *MockCategoryStorer_CleanUp_Call) RunAndReturn(run func(string) error)
 	return _c
 }
 
-// InsertDataset provides a mock function with given fields: dataset
-func (_m *MockCategoryStorer) InsertDataset(dataset []model.CategoryDataset) error {
-	ret := _m.Called(dataset)
+// CleanUpPartition provides a mock function with given fields: version, p
+func (_m *MockCategoryStorer) CleanUpPartition(version string, p Partition) error {
+	ret := _m.Called(version, p)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertDataset")
+		panic("no return value specified for CleanUpPartition")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]model.CategoryDataset) error); ok {
-		r0 = rf(dataset)
+	if rf, ok := ret.Get(0).(func(string, Partition) error); ok {
+		r0 = rf(version, p)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -84,57 +256,149 @@ func (_m *MockCategoryStorer) InsertDataset(dataset []model.CategoryDataset) err
 	return r0
 }
 
-// MockCategoryStorer_InsertDataset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertDataset'
-type MockCategoryStorer_InsertDataset_Call struct {
+// MockCategoryStorer_CleanUpPartition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CleanUpPartition'
+type MockCategoryStorer_CleanUpPartition_Call struct {
 	*mock.Call
 }
 
-// InsertDataset is a helper method to define mock.On call
-//   - dataset []model.CategoryDataset
-func (_e *MockCategoryStorer_Expecter) InsertDataset(dataset interface{}) *MockCategoryStorer_InsertDataset_Call {
-	return &MockCategoryStorer_InsertDataset_Call{Call: _e.mock.On("InsertDataset", dataset)}
+// CleanUpPartition is a helper method to define mock.On call
+//   - version string
+//   - p Partition
+func (_e *MockCategoryStorer_Expecter) CleanUpPartition(version interface{}, p interface{}) *MockCategoryStorer_CleanUpPartition_Call {
+	return &MockCategoryStorer_CleanUpPartition_Call{Call: _e.mock.On("CleanUpPartition", version, p)}
 }
 
-func (_c *MockCategoryStorer_InsertDataset_Call) Run(run func(dataset []model.CategoryDataset)) *MockCategoryStorer_InsertDataset_Call {
+func (_c *MockCategoryStorer_CleanUpPartition_Call) Run(run func(version string, p Partition)) *MockCategoryStorer_CleanUpPartition_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].([]model.CategoryDataset))
+		run(args[0].(string), args[1].(Partition))
 	})
 	return _c
 }
 
-func (_c *MockCategoryStorer_InsertDataset_Call) Return(_a0 error) *MockCategoryStorer_InsertDataset_Call {
+func (_c *MockCategoryStorer_CleanUpPartition_Call) Return(_a0 error) *MockCategoryStorer_CleanUpPartition_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockCategoryStorer_InsertDataset_Call) RunAndReturn(run func([]model.CategoryDataset) error) *MockCategoryStorer_InsertDataset_Call {
+func (_c *MockCategoryStorer_CleanUpPartition_Call) RunAndReturn(run func(string, Partition) error) *MockCategoryStorer_CleanUpPartition_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// MatchedCategory provides a mock function with given fields:
-func (_m *MockCategoryStorer) MatchedCategory() ([]model.MatchCategory, error) {
-	ret := _m.Called()
+// CleanUpRows provides a mock function with given fields: version, matchCategoryIDs
+func (_m *MockCategoryStorer) CleanUpRows(version string, matchCategoryIDs []int32) error {
+	ret := _m.Called(version, matchCategoryIDs)
 
 	if len(ret) == 0 {
-		panic("no return value specified for MatchedCategory")
+		panic("no return value specified for CleanUpRows")
 	}
 
-	var r0 []model.MatchCategory
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []int32) error); ok {
+		r0 = rf(version, matchCategoryIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_CleanUpRows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CleanUpRows'
+type MockCategoryStorer_CleanUpRows_Call struct {
+	*mock.Call
+}
+
+// CleanUpRows is a helper method to define mock.On call
+//   - version string
+//   - matchCategoryIDs []int32
+func (_e *MockCategoryStorer_Expecter) CleanUpRows(version interface{}, matchCategoryIDs interface{}) *MockCategoryStorer_CleanUpRows_Call {
+	return &MockCategoryStorer_CleanUpRows_Call{Call: _e.mock.On("CleanUpRows", version, matchCategoryIDs)}
+}
+
+func (_c *MockCategoryStorer_CleanUpRows_Call) Run(run func(version string, matchCategoryIDs []int32)) *MockCategoryStorer_CleanUpRows_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].([]int32))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_CleanUpRows_Call) Return(_a0 error) *MockCategoryStorer_CleanUpRows_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_CleanUpRows_Call) RunAndReturn(run func(string, []int32) error) *MockCategoryStorer_CleanUpRows_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCheckpoint provides a mock function with given fields: version
+func (_m *MockCategoryStorer) DeleteCheckpoint(version string) error {
+	ret := _m.Called(version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCheckpoint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_DeleteCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCheckpoint'
+type MockCategoryStorer_DeleteCheckpoint_Call struct {
+	*mock.Call
+}
+
+// DeleteCheckpoint is a helper method to define mock.On call
+//   - version string
+func (_e *MockCategoryStorer_Expecter) DeleteCheckpoint(version interface{}) *MockCategoryStorer_DeleteCheckpoint_Call {
+	return &MockCategoryStorer_DeleteCheckpoint_Call{Call: _e.mock.On("DeleteCheckpoint", version)}
+}
+
+func (_c *MockCategoryStorer_DeleteCheckpoint_Call) Run(run func(version string)) *MockCategoryStorer_DeleteCheckpoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_DeleteCheckpoint_Call) Return(_a0 error) *MockCategoryStorer_DeleteCheckpoint_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_DeleteCheckpoint_Call) RunAndReturn(run func(string) error) *MockCategoryStorer_DeleteCheckpoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FanOutInfo provides a mock function with given fields: id
+func (_m *MockCategoryStorer) FanOutInfo(id string) (FanOutInfo, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FanOutInfo")
+	}
+
+	var r0 FanOutInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]model.MatchCategory, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(string) (FanOutInfo, error)); ok {
+		return rf(id)
 	}
-	if rf, ok := ret.Get(0).(func() []model.MatchCategory); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(string) FanOutInfo); ok {
+		r0 = rf(id)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.MatchCategory)
-		}
+		r0 = ret.Get(0).(FanOutInfo)
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -142,56 +406,55 @@ func (_m *MockCategoryStorer) MatchedCategory() ([]model.MatchCategory, error) {
 	return r0, r1
 }
 
-// MockCategoryStorer_MatchedCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MatchedCategory'
-type MockCategoryStorer_MatchedCategory_Call struct {
+// MockCategoryStorer_FanOutInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FanOutInfo'
+type MockCategoryStorer_FanOutInfo_Call struct {
 	*mock.Call
 }
 
-// MatchedCategory is a helper method to define mock.On call
-func (_e *MockCategoryStorer_Expecter) MatchedCategory() *MockCategoryStorer_MatchedCategory_Call {
-	return &MockCategoryStorer_MatchedCategory_Call{Call: _e.mock.On("MatchedCategory")}
+// FanOutInfo is a helper method to define mock.On call
+//   - id string
+func (_e *MockCategoryStorer_Expecter) FanOutInfo(id interface{}) *MockCategoryStorer_FanOutInfo_Call {
+	return &MockCategoryStorer_FanOutInfo_Call{Call: _e.mock.On("FanOutInfo", id)}
 }
 
-func (_c *MockCategoryStorer_MatchedCategory_Call) Run(run func()) *MockCategoryStorer_MatchedCategory_Call {
+func (_c *MockCategoryStorer_FanOutInfo_Call) Run(run func(id string)) *MockCategoryStorer_FanOutInfo_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(string))
 	})
 	return _c
 }
 
-func (_c *MockCategoryStorer_MatchedCategory_Call) Return(_a0 []model.MatchCategory, _a1 error) *MockCategoryStorer_MatchedCategory_Call {
+func (_c *MockCategoryStorer_FanOutInfo_Call) Return(_a0 FanOutInfo, _a1 error) *MockCategoryStorer_FanOutInfo_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockCategoryStorer_MatchedCategory_Call) RunAndReturn(run func() ([]model.MatchCategory, error)) *MockCategoryStorer_MatchedCategory_Call {
+func (_c *MockCategoryStorer_FanOutInfo_Call) RunAndReturn(run func(string) (FanOutInfo, error)) *MockCategoryStorer_FanOutInfo_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// OriginalCategory provides a mock function with given fields:
-func (_m *MockCategoryStorer) OriginalCategory() (Category, error) {
-	ret := _m.Called()
+// FinishPartition provides a mock function with given fields: version, index, rows
+func (_m *MockCategoryStorer) FinishPartition(version string, index int, rows int) (int, error) {
+	ret := _m.Called(version, index, rows)
 
 	if len(ret) == 0 {
-		panic("no return value specified for OriginalCategory")
+		panic("no return value specified for FinishPartition")
 	}
 
-	var r0 Category
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (Category, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(string, int, int) (int, error)); ok {
+		return rf(version, index, rows)
 	}
-	if rf, ok := ret.Get(0).(func() Category); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(string, int, int) int); ok {
+		r0 = rf(version, index, rows)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(Category)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = rf(version, index, rows)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -199,29 +462,929 @@ func (_m *MockCategoryStorer) OriginalCategory() (Category, error) {
 	return r0, r1
 }
 
-// MockCategoryStorer_OriginalCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OriginalCategory'
-type MockCategoryStorer_OriginalCategory_Call struct {
+// MockCategoryStorer_FinishPartition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FinishPartition'
+type MockCategoryStorer_FinishPartition_Call struct {
 	*mock.Call
 }
 
-// OriginalCategory is a helper method to define mock.On call
-func (_e *MockCategoryStorer_Expecter) OriginalCategory() *MockCategoryStorer_OriginalCategory_Call {
-	return &MockCategoryStorer_OriginalCategory_Call{Call: _e.mock.On("OriginalCategory")}
+// FinishPartition is a helper method to define mock.On call
+//   - version string
+//   - index int
+//   - rows int
+func (_e *MockCategoryStorer_Expecter) FinishPartition(version interface{}, index interface{}, rows interface{}) *MockCategoryStorer_FinishPartition_Call {
+	return &MockCategoryStorer_FinishPartition_Call{Call: _e.mock.On("FinishPartition", version, index, rows)}
 }
 
-func (_c *MockCategoryStorer_OriginalCategory_Call) Run(run func()) *MockCategoryStorer_OriginalCategory_Call {
+func (_c *MockCategoryStorer_FinishPartition_Call) Run(run func(version string, index int, rows int)) *MockCategoryStorer_FinishPartition_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(string), args[1].(int), args[2].(int))
 	})
 	return _c
 }
 
-func (_c *MockCategoryStorer_OriginalCategory_Call) Return(_a0 Category, _a1 error) *MockCategoryStorer_OriginalCategory_Call {
+func (_c *MockCategoryStorer_FinishPartition_Call) Return(_a0 int, _a1 error) *MockCategoryStorer_FinishPartition_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockCategoryStorer_OriginalCategory_Call) RunAndReturn(run func() (Category, error)) *MockCategoryStorer_OriginalCategory_Call {
+func (_c *MockCategoryStorer_FinishPartition_Call) RunAndReturn(run func(string, int, int) (int, error)) *MockCategoryStorer_FinishPartition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertDataset provides a mock function with given fields: dataset
+func (_m *MockCategoryStorer) InsertDataset(dataset []model.CategoryDataset) error {
+	ret := _m.Called(dataset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertDataset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]model.CategoryDataset) error); ok {
+		r0 = rf(dataset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_InsertDataset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertDataset'
+type MockCategoryStorer_InsertDataset_Call struct {
+	*mock.Call
+}
+
+// InsertDataset is a helper method to define mock.On call
+//   - dataset []model.CategoryDataset
+func (_e *MockCategoryStorer_Expecter) InsertDataset(dataset interface{}) *MockCategoryStorer_InsertDataset_Call {
+	return &MockCategoryStorer_InsertDataset_Call{Call: _e.mock.On("InsertDataset", dataset)}
+}
+
+func (_c *MockCategoryStorer_InsertDataset_Call) Run(run func(dataset []model.CategoryDataset)) *MockCategoryStorer_InsertDataset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]model.CategoryDataset))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_InsertDataset_Call) Return(_a0 error) *MockCategoryStorer_InsertDataset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_InsertDataset_Call) RunAndReturn(run func([]model.CategoryDataset) error) *MockCategoryStorer_InsertDataset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockVersion provides a mock function with given fields: ctx, version, shared, wait
+func (_m *MockCategoryStorer) LockVersion(ctx context.Context, version string, shared bool, wait time.Duration) (func() error, error) {
+	ret := _m.Called(ctx, version, shared, wait)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockVersion")
+	}
+
+	var r0 func() error
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, time.Duration) (func() error, error)); ok {
+		return rf(ctx, version, shared, wait)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, time.Duration) func() error); ok {
+		r0 = rf(ctx, version, shared, wait)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func() error)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, time.Duration) error); ok {
+		r1 = rf(ctx, version, shared, wait)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_LockVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockVersion'
+type MockCategoryStorer_LockVersion_Call struct {
+	*mock.Call
+}
+
+// LockVersion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - version string
+//   - shared bool
+//   - wait time.Duration
+func (_e *MockCategoryStorer_Expecter) LockVersion(ctx interface{}, version interface{}, shared interface{}, wait interface{}) *MockCategoryStorer_LockVersion_Call {
+	return &MockCategoryStorer_LockVersion_Call{Call: _e.mock.On("LockVersion", ctx, version, shared, wait)}
+}
+
+func (_c *MockCategoryStorer_LockVersion_Call) Run(run func(ctx context.Context, version string, shared bool, wait time.Duration)) *MockCategoryStorer_LockVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(bool), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_LockVersion_Call) Return(_a0 func() error, _a1 error) *MockCategoryStorer_LockVersion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_LockVersion_Call) RunAndReturn(run func(context.Context, string, bool, time.Duration) (func() error, error)) *MockCategoryStorer_LockVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MatchIDRange provides a mock function with given fields: filter
+func (_m *MockCategoryStorer) MatchIDRange(filter MatchFilter) (int32, int32, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MatchIDRange")
+	}
+
+	var r0 int32
+	var r1 int32
+	var r2 error
+	if rf, ok := ret.Get(0).(func(MatchFilter) (int32, int32, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(MatchFilter) int32); ok {
+		r0 = rf(filter)
+	} else {
+		r0 = ret.Get(0).(int32)
+	}
+
+	if rf, ok := ret.Get(1).(func(MatchFilter) int32); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Get(1).(int32)
+	}
+
+	if rf, ok := ret.Get(2).(func(MatchFilter) error); ok {
+		r2 = rf(filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockCategoryStorer_MatchIDRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MatchIDRange'
+type MockCategoryStorer_MatchIDRange_Call struct {
+	*mock.Call
+}
+
+// MatchIDRange is a helper method to define mock.On call
+//   - filter MatchFilter
+func (_e *MockCategoryStorer_Expecter) MatchIDRange(filter interface{}) *MockCategoryStorer_MatchIDRange_Call {
+	return &MockCategoryStorer_MatchIDRange_Call{Call: _e.mock.On("MatchIDRange", filter)}
+}
+
+func (_c *MockCategoryStorer_MatchIDRange_Call) Run(run func(filter MatchFilter)) *MockCategoryStorer_MatchIDRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(MatchFilter))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_MatchIDRange_Call) Return(_a0 int32, _a1 int32, _a2 error) *MockCategoryStorer_MatchIDRange_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockCategoryStorer_MatchIDRange_Call) RunAndReturn(run func(MatchFilter) (int32, int32, error)) *MockCategoryStorer_MatchIDRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MatchedCategory provides a mock function with given fields: filter
+func (_m *MockCategoryStorer) MatchedCategory(filter MatchFilter) ([]model.MatchCategory, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MatchedCategory")
+	}
+
+	var r0 []model.MatchCategory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(MatchFilter) ([]model.MatchCategory, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(MatchFilter) []model.MatchCategory); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.MatchCategory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(MatchFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_MatchedCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MatchedCategory'
+type MockCategoryStorer_MatchedCategory_Call struct {
+	*mock.Call
+}
+
+// MatchedCategory is a helper method to define mock.On call
+//   - filter MatchFilter
+func (_e *MockCategoryStorer_Expecter) MatchedCategory(filter interface{}) *MockCategoryStorer_MatchedCategory_Call {
+	return &MockCategoryStorer_MatchedCategory_Call{Call: _e.mock.On("MatchedCategory", filter)}
+}
+
+func (_c *MockCategoryStorer_MatchedCategory_Call) Run(run func(filter MatchFilter)) *MockCategoryStorer_MatchedCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(MatchFilter))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_MatchedCategory_Call) Return(_a0 []model.MatchCategory, _a1 error) *MockCategoryStorer_MatchedCategory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_MatchedCategory_Call) RunAndReturn(run func(MatchFilter) ([]model.MatchCategory, error)) *MockCategoryStorer_MatchedCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MoveAliases provides a mock function with given fields: version, names
+func (_m *MockCategoryStorer) MoveAliases(version string, names []string) error {
+	ret := _m.Called(version, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveAliases")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
+		r0 = rf(version, names)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_MoveAliases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MoveAliases'
+type MockCategoryStorer_MoveAliases_Call struct {
+	*mock.Call
+}
+
+// MoveAliases is a helper method to define mock.On call
+//   - version string
+//   - names []string
+func (_e *MockCategoryStorer_Expecter) MoveAliases(version interface{}, names interface{}) *MockCategoryStorer_MoveAliases_Call {
+	return &MockCategoryStorer_MoveAliases_Call{Call: _e.mock.On("MoveAliases", version, names)}
+}
+
+func (_c *MockCategoryStorer_MoveAliases_Call) Run(run func(version string, names []string)) *MockCategoryStorer_MoveAliases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_MoveAliases_Call) Return(_a0 error) *MockCategoryStorer_MoveAliases_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_MoveAliases_Call) RunAndReturn(run func(string, []string) error) *MockCategoryStorer_MoveAliases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OriginalCategory provides a mock function with given fields:
+func (_m *MockCategoryStorer) OriginalCategory() (Category, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for OriginalCategory")
+	}
+
+	var r0 Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (Category, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() Category); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_OriginalCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OriginalCategory'
+type MockCategoryStorer_OriginalCategory_Call struct {
+	*mock.Call
+}
+
+// OriginalCategory is a helper method to define mock.On call
+func (_e *MockCategoryStorer_Expecter) OriginalCategory() *MockCategoryStorer_OriginalCategory_Call {
+	return &MockCategoryStorer_OriginalCategory_Call{Call: _e.mock.On("OriginalCategory")}
+}
+
+func (_c *MockCategoryStorer_OriginalCategory_Call) Run(run func()) *MockCategoryStorer_OriginalCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_OriginalCategory_Call) Return(_a0 Category, _a1 error) *MockCategoryStorer_OriginalCategory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_OriginalCategory_Call) RunAndReturn(run func() (Category, error)) *MockCategoryStorer_OriginalCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Partitions provides a mock function with given fields: version
+func (_m *MockCategoryStorer) Partitions(version string) ([]PartitionInfo, error) {
+	ret := _m.Called(version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Partitions")
+	}
+
+	var r0 []PartitionInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]PartitionInfo, error)); ok {
+		return rf(version)
+	}
+	if rf, ok := ret.Get(0).(func(string) []PartitionInfo); ok {
+		r0 = rf(version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]PartitionInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_Partitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Partitions'
+type MockCategoryStorer_Partitions_Call struct {
+	*mock.Call
+}
+
+// Partitions is a helper method to define mock.On call
+//   - version string
+func (_e *MockCategoryStorer_Expecter) Partitions(version interface{}) *MockCategoryStorer_Partitions_Call {
+	return &MockCategoryStorer_Partitions_Call{Call: _e.mock.On("Partitions", version)}
+}
+
+func (_c *MockCategoryStorer_Partitions_Call) Run(run func(version string)) *MockCategoryStorer_Partitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_Partitions_Call) Return(_a0 []PartitionInfo, _a1 error) *MockCategoryStorer_Partitions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_Partitions_Call) RunAndReturn(run func(string) ([]PartitionInfo, error)) *MockCategoryStorer_Partitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseVersion provides a mock function with given fields: version
+func (_m *MockCategoryStorer) ReleaseVersion(version string) error {
+	ret := _m.Called(version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_ReleaseVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseVersion'
+type MockCategoryStorer_ReleaseVersion_Call struct {
+	*mock.Call
+}
+
+// ReleaseVersion is a helper method to define mock.On call
+//   - version string
+func (_e *MockCategoryStorer_Expecter) ReleaseVersion(version interface{}) *MockCategoryStorer_ReleaseVersion_Call {
+	return &MockCategoryStorer_ReleaseVersion_Call{Call: _e.mock.On("ReleaseVersion", version)}
+}
+
+func (_c *MockCategoryStorer_ReleaseVersion_Call) Run(run func(version string)) *MockCategoryStorer_ReleaseVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_ReleaseVersion_Call) Return(_a0 error) *MockCategoryStorer_ReleaseVersion_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_ReleaseVersion_Call) RunAndReturn(run func(string) error) *MockCategoryStorer_ReleaseVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReserveVersion provides a mock function with given fields: version
+func (_m *MockCategoryStorer) ReserveVersion(version string) (bool, error) {
+	ret := _m.Called(version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveVersion")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(version)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(version)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_ReserveVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReserveVersion'
+type MockCategoryStorer_ReserveVersion_Call struct {
+	*mock.Call
+}
+
+// ReserveVersion is a helper method to define mock.On call
+//   - version string
+func (_e *MockCategoryStorer_Expecter) ReserveVersion(version interface{}) *MockCategoryStorer_ReserveVersion_Call {
+	return &MockCategoryStorer_ReserveVersion_Call{Call: _e.mock.On("ReserveVersion", version)}
+}
+
+func (_c *MockCategoryStorer_ReserveVersion_Call) Run(run func(version string)) *MockCategoryStorer_ReserveVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_ReserveVersion_Call) Return(_a0 bool, _a1 error) *MockCategoryStorer_ReserveVersion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_ReserveVersion_Call) RunAndReturn(run func(string) (bool, error)) *MockCategoryStorer_ReserveVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveCheckpoint provides a mock function with given fields: cp
+func (_m *MockCategoryStorer) SaveCheckpoint(cp Checkpoint) error {
+	ret := _m.Called(cp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveCheckpoint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(Checkpoint) error); ok {
+		r0 = rf(cp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_SaveCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveCheckpoint'
+type MockCategoryStorer_SaveCheckpoint_Call struct {
+	*mock.Call
+}
+
+// SaveCheckpoint is a helper method to define mock.On call
+//   - cp Checkpoint
+func (_e *MockCategoryStorer_Expecter) SaveCheckpoint(cp interface{}) *MockCategoryStorer_SaveCheckpoint_Call {
+	return &MockCategoryStorer_SaveCheckpoint_Call{Call: _e.mock.On("SaveCheckpoint", cp)}
+}
+
+func (_c *MockCategoryStorer_SaveCheckpoint_Call) Run(run func(cp Checkpoint)) *MockCategoryStorer_SaveCheckpoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(Checkpoint))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_SaveCheckpoint_Call) Return(_a0 error) *MockCategoryStorer_SaveCheckpoint_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_SaveCheckpoint_Call) RunAndReturn(run func(Checkpoint) error) *MockCategoryStorer_SaveCheckpoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SavePartitions provides a mock function with given fields: fo
+func (_m *MockCategoryStorer) SavePartitions(fo FanOutInfo) error {
+	ret := _m.Called(fo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SavePartitions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(FanOutInfo) error); ok {
+		r0 = rf(fo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_SavePartitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SavePartitions'
+type MockCategoryStorer_SavePartitions_Call struct {
+	*mock.Call
+}
+
+// SavePartitions is a helper method to define mock.On call
+//   - fo FanOutInfo
+func (_e *MockCategoryStorer_Expecter) SavePartitions(fo interface{}) *MockCategoryStorer_SavePartitions_Call {
+	return &MockCategoryStorer_SavePartitions_Call{Call: _e.mock.On("SavePartitions", fo)}
+}
+
+func (_c *MockCategoryStorer_SavePartitions_Call) Run(run func(fo FanOutInfo)) *MockCategoryStorer_SavePartitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(FanOutInfo))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_SavePartitions_Call) Return(_a0 error) *MockCategoryStorer_SavePartitions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_SavePartitions_Call) RunAndReturn(run func(FanOutInfo) error) *MockCategoryStorer_SavePartitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveSnapshot provides a mock function with given fields: version, tree
+func (_m *MockCategoryStorer) SaveSnapshot(version string, tree []CategoryNode) error {
+	ret := _m.Called(version, tree)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSnapshot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []CategoryNode) error); ok {
+		r0 = rf(version, tree)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_SaveSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSnapshot'
+type MockCategoryStorer_SaveSnapshot_Call struct {
+	*mock.Call
+}
+
+// SaveSnapshot is a helper method to define mock.On call
+//   - version string
+//   - tree []CategoryNode
+func (_e *MockCategoryStorer_Expecter) SaveSnapshot(version interface{}, tree interface{}) *MockCategoryStorer_SaveSnapshot_Call {
+	return &MockCategoryStorer_SaveSnapshot_Call{Call: _e.mock.On("SaveSnapshot", version, tree)}
+}
+
+func (_c *MockCategoryStorer_SaveSnapshot_Call) Run(run func(version string, tree []CategoryNode)) *MockCategoryStorer_SaveSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].([]CategoryNode))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_SaveSnapshot_Call) Return(_a0 error) *MockCategoryStorer_SaveSnapshot_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_SaveSnapshot_Call) RunAndReturn(run func(string, []CategoryNode) error) *MockCategoryStorer_SaveSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveVersion provides a mock function with given fields: version, lastMatchID, actor
+func (_m *MockCategoryStorer) SaveVersion(version string, lastMatchID int32, actor string) error {
+	ret := _m.Called(version, lastMatchID, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int32, string) error); ok {
+		r0 = rf(version, lastMatchID, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_SaveVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveVersion'
+type MockCategoryStorer_SaveVersion_Call struct {
+	*mock.Call
+}
+
+// SaveVersion is a helper method to define mock.On call
+//   - version string
+//   - lastMatchID int32
+//   - actor string
+func (_e *MockCategoryStorer_Expecter) SaveVersion(version interface{}, lastMatchID interface{}, actor interface{}) *MockCategoryStorer_SaveVersion_Call {
+	return &MockCategoryStorer_SaveVersion_Call{Call: _e.mock.On("SaveVersion", version, lastMatchID, actor)}
+}
+
+func (_c *MockCategoryStorer_SaveVersion_Call) Run(run func(version string, lastMatchID int32, actor string)) *MockCategoryStorer_SaveVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int32), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_SaveVersion_Call) Return(_a0 error) *MockCategoryStorer_SaveVersion_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_SaveVersion_Call) RunAndReturn(run func(string, int32, string) error) *MockCategoryStorer_SaveVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetVersionStatus provides a mock function with given fields: version, status
+func (_m *MockCategoryStorer) SetVersionStatus(version string, status string) error {
+	ret := _m.Called(version, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetVersionStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(version, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCategoryStorer_SetVersionStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetVersionStatus'
+type MockCategoryStorer_SetVersionStatus_Call struct {
+	*mock.Call
+}
+
+// SetVersionStatus is a helper method to define mock.On call
+//   - version string
+//   - status string
+func (_e *MockCategoryStorer_Expecter) SetVersionStatus(version interface{}, status interface{}) *MockCategoryStorer_SetVersionStatus_Call {
+	return &MockCategoryStorer_SetVersionStatus_Call{Call: _e.mock.On("SetVersionStatus", version, status)}
+}
+
+func (_c *MockCategoryStorer_SetVersionStatus_Call) Run(run func(version string, status string)) *MockCategoryStorer_SetVersionStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_SetVersionStatus_Call) Return(_a0 error) *MockCategoryStorer_SetVersionStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCategoryStorer_SetVersionStatus_Call) RunAndReturn(run func(string, string) error) *MockCategoryStorer_SetVersionStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Snapshot provides a mock function with given fields: version
+func (_m *MockCategoryStorer) Snapshot(version string) ([]CategoryNode, error) {
+	ret := _m.Called(version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Snapshot")
+	}
+
+	var r0 []CategoryNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]CategoryNode, error)); ok {
+		return rf(version)
+	}
+	if rf, ok := ret.Get(0).(func(string) []CategoryNode); ok {
+		r0 = rf(version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]CategoryNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_Snapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Snapshot'
+type MockCategoryStorer_Snapshot_Call struct {
+	*mock.Call
+}
+
+// Snapshot is a helper method to define mock.On call
+//   - version string
+func (_e *MockCategoryStorer_Expecter) Snapshot(version interface{}) *MockCategoryStorer_Snapshot_Call {
+	return &MockCategoryStorer_Snapshot_Call{Call: _e.mock.On("Snapshot", version)}
+}
+
+func (_c *MockCategoryStorer_Snapshot_Call) Run(run func(version string)) *MockCategoryStorer_Snapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_Snapshot_Call) Return(_a0 []CategoryNode, _a1 error) *MockCategoryStorer_Snapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_Snapshot_Call) RunAndReturn(run func(string) ([]CategoryNode, error)) *MockCategoryStorer_Snapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VersionInfo provides a mock function with given fields: version
+func (_m *MockCategoryStorer) VersionInfo(version string) (VersionInfo, error) {
+	ret := _m.Called(version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VersionInfo")
+	}
+
+	var r0 VersionInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (VersionInfo, error)); ok {
+		return rf(version)
+	}
+	if rf, ok := ret.Get(0).(func(string) VersionInfo); ok {
+		r0 = rf(version)
+	} else {
+		r0 = ret.Get(0).(VersionInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_VersionInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VersionInfo'
+type MockCategoryStorer_VersionInfo_Call struct {
+	*mock.Call
+}
+
+// VersionInfo is a helper method to define mock.On call
+//   - version string
+func (_e *MockCategoryStorer_Expecter) VersionInfo(version interface{}) *MockCategoryStorer_VersionInfo_Call {
+	return &MockCategoryStorer_VersionInfo_Call{Call: _e.mock.On("VersionInfo", version)}
+}
+
+func (_c *MockCategoryStorer_VersionInfo_Call) Run(run func(version string)) *MockCategoryStorer_VersionInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_VersionInfo_Call) Return(_a0 VersionInfo, _a1 error) *MockCategoryStorer_VersionInfo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_VersionInfo_Call) RunAndReturn(run func(string) (VersionInfo, error)) *MockCategoryStorer_VersionInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VersionNames provides a mock function with given fields:
+func (_m *MockCategoryStorer) VersionNames() ([]string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for VersionNames")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCategoryStorer_VersionNames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VersionNames'
+type MockCategoryStorer_VersionNames_Call struct {
+	*mock.Call
+}
+
+// VersionNames is a helper method to define mock.On call
+func (_e *MockCategoryStorer_Expecter) VersionNames() *MockCategoryStorer_VersionNames_Call {
+	return &MockCategoryStorer_VersionNames_Call{Call: _e.mock.On("VersionNames")}
+}
+
+func (_c *MockCategoryStorer_VersionNames_Call) Run(run func()) *MockCategoryStorer_VersionNames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockCategoryStorer_VersionNames_Call) Return(_a0 []string, _a1 error) *MockCategoryStorer_VersionNames_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCategoryStorer_VersionNames_Call) RunAndReturn(run func() ([]string, error)) *MockCategoryStorer_VersionNames_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,184 @@
+package transform
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Version template tokens. A Config.Version may contain at most one of them, which is replaced
+// when the dataset is generated:
+//   - VersionTokenTimestamp: the current UTC time, e.g. "20240630T120000Z".
+//   - VersionTokenCounter: one more than the highest counter among existing versions with the same template.
+//   - VersionTokenMajor, VersionTokenMinor, VersionTokenPatch: the highest existing semantic version
+//     with the same template, bumped at the given position ("1.0.0", "0.1.0" or "0.0.1" when there is none).
+const (
+	VersionTokenTimestamp = "{timestamp}"
+	VersionTokenCounter   = "{counter}"
+	VersionTokenMajor     = "{semver:major}"
+	VersionTokenMinor     = "{semver:minor}"
+	VersionTokenPatch     = "{semver:patch}"
+)
+
+// DefaultVersionTemplate is used when Config.Version is empty.
+const DefaultVersionTemplate = "v" + VersionTokenTimestamp
+
+const versionTimestampLayout = "20060102T150405Z"
+
+// maxReserveAttempts is how often ResolveVersion resolves a template again after another run
+// reserved the resolved name first.
+const maxReserveAttempts = 5
+
+var (
+	ErrInvalidVersionTemplate = errors.New("invalid version template")
+	ErrVersionNotReserved     = errors.New("could not reserve a version name for the template")
+)
+
+var versionTokenRe = regexp.MustCompile(`\{[^{}]*\}`)
+
+// versionTemplate is a Config.Version split around its template token.
+// A version without a token is used as it is.
+type versionTemplate struct {
+	prefix string
+	token  string
+	suffix string
+}
+
+func parseVersionTemplate(version string) (versionTemplate, error) {
+	version = cmp.Or(version, DefaultVersionTemplate)
+	loc := versionTokenRe.FindAllStringIndex(version, -1)
+	switch len(loc) {
+	case 0:
+		return versionTemplate{prefix: version}, nil
+	case 1:
+	default:
+		return versionTemplate{}, fmt.Errorf("%w: more than one token in %q", ErrInvalidVersionTemplate, version)
+	}
+	t := versionTemplate{
+		prefix: version[:loc[0][0]],
+		token:  version[loc[0][0]:loc[0][1]],
+		suffix: version[loc[0][1]:],
+	}
+	switch t.token {
+	case VersionTokenTimestamp, VersionTokenCounter, VersionTokenMajor, VersionTokenMinor, VersionTokenPatch:
+	default:
+		return versionTemplate{}, fmt.Errorf("%w: unknown token %s", ErrInvalidVersionTemplate, t.token)
+	}
+	return t, nil
+}
+
+// needsExisting reports whether resolving the template depends on the versions generated before.
+func (t versionTemplate) needsExisting() bool {
+	return t.token != "" && t.token != VersionTokenTimestamp
+}
+
+// resolve returns the concrete version name for the template.
+// existing holds the names of all known versions; it is only used by the counter and semver tokens.
+func (t versionTemplate) resolve(now time.Time, existing []string) string {
+	switch t.token {
+	case "":
+		return t.prefix
+	case VersionTokenTimestamp:
+		return t.prefix + now.UTC().Format(versionTimestampLayout) + t.suffix
+	case VersionTokenCounter:
+		re := t.pattern(`(\d+)`)
+		next := 1
+		for _, v := range existing {
+			if m := re.FindStringSubmatch(v); m != nil {
+				n, _ := strconv.Atoi(m[1])
+				next = max(next, n+1)
+			}
+		}
+		return t.prefix + strconv.Itoa(next) + t.suffix
+	default:
+		re := t.pattern(`(\d+)\.(\d+)\.(\d+)`)
+		var latest [3]int
+		for _, v := range existing {
+			m := re.FindStringSubmatch(v)
+			if m == nil {
+				continue
+			}
+			var sv [3]int
+			for i := range sv {
+				sv[i], _ = strconv.Atoi(m[i+1])
+			}
+			if compareSemver(sv, latest) > 0 {
+				latest = sv
+			}
+		}
+		return t.prefix + bumpSemver(latest, t.token) + t.suffix
+	}
+}
+
+func (t versionTemplate) pattern(value string) *regexp.Regexp {
+	return regexp.MustCompile("^" + regexp.QuoteMeta(t.prefix) + value + regexp.QuoteMeta(t.suffix) + "$")
+}
+
+func compareSemver(a, b [3]int) int {
+	for i := range a {
+		if c := cmp.Compare(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func bumpSemver(v [3]int, token string) string {
+	switch token {
+	case VersionTokenMajor:
+		v = [3]int{v[0] + 1, 0, 0}
+	case VersionTokenMinor:
+		v = [3]int{v[0], v[1] + 1, 0}
+	default:
+		v = [3]int{v[0], v[1], v[2] + 1}
+	}
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// ResolveVersion replaces the template in Config.Version (DefaultVersionTemplate when empty)
+// with a concrete version name. GenerateDataset calls it first; calling it beforehand lets the caller
+// learn the version before the generation starts. A concrete version resolves to itself.
+// A resolved name is reserved with CategoryStorer.ReserveVersion before it is used, so that concurrent runs
+// of the same template never get the same name: when another run reserved it first, the template is resolved
+// again against the updated names (timestamps move on by a second), up to maxReserveAttempts times,
+// after which ErrVersionNotReserved is returned.
+func (t *Transform) ResolveVersion() error {
+	tmpl, err := parseVersionTemplate(t.config.Version)
+	if err != nil {
+		return err
+	}
+	if tmpl.token == "" {
+		t.config.Version = tmpl.prefix
+		return nil
+	}
+	now := t.now()
+	for attempt := range maxReserveAttempts {
+		var existing []string
+		if tmpl.needsExisting() {
+			if existing, err = t.catStore.VersionNames(); err != nil {
+				return err
+			}
+		}
+		version := tmpl.resolve(now.Add(time.Duration(attempt)*time.Second), existing)
+		reserved, rErr := t.catStore.ReserveVersion(version)
+		if rErr != nil {
+			return rErr
+		}
+		if reserved {
+			t.log.Info("resolved version", "template", t.config.Version, "version", version)
+			t.config.Version, t.reserved = version, true
+			return nil
+		}
+		t.log.Info("version already taken, resolving again", "template", t.config.Version, "version", version)
+	}
+	return fmt.Errorf("%w: %s", ErrVersionNotReserved, t.config.Version)
+}
+
+// Version returns the version the dataset is generated for. Once GenerateDataset has started,
+// templates in Config.Version have been resolved to the concrete version name.
+func (t *Transform) Version() string {
+	return t.config.Version
+}
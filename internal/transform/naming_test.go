@@ -0,0 +1,151 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionTemplateResolve(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 5, 0, time.UTC)
+	existing := []string{"cat-3", "cat-10", "cat-x", "other-99", "m-v1.2.3", "m-v1.10.0", "m-v0.9.9"}
+	tests := []struct {
+		name     string
+		version  string
+		existing []string
+		want     string
+		wantErr  error
+	}{
+		{name: "Static", version: "v1-lambda", want: "v1-lambda"},
+		{name: "Default", version: "", want: "v20240630T120005Z"},
+		{name: "Timestamp", version: "nightly-{timestamp}", want: "nightly-20240630T120005Z"},
+		{name: "Counter", version: "cat-{counter}", existing: existing, want: "cat-11"},
+		{name: "First counter", version: "new-{counter}", existing: existing, want: "new-1"},
+		{name: "Patch", version: "m-v{semver:patch}", existing: existing, want: "m-v1.10.1"},
+		{name: "Minor", version: "m-v{semver:minor}", existing: existing, want: "m-v1.11.0"},
+		{name: "Major", version: "m-v{semver:major}", existing: existing, want: "m-v2.0.0"},
+		{name: "First minor", version: "n-v{semver:minor}", existing: existing, want: "n-v0.1.0"},
+		{name: "Unknown token", version: "v{build}", wantErr: ErrInvalidVersionTemplate},
+		{name: "Two tokens", version: "{counter}-{timestamp}", wantErr: ErrInvalidVersionTemplate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseVersionTemplate(tt.version)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tmpl.resolve(now, tt.existing))
+		})
+	}
+}
+
+func TestTransformResolveVersion(t *testing.T) {
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().VersionNames().Return([]string{"cat-1", "cat-2"}, nil)
+	mockStorer.EXPECT().ReserveVersion("cat-3").Return(true, nil)
+
+	tr := NewTransform(testLogger(), mockStorer, Config{Version: "cat-{counter}"})
+	require.NoError(t, tr.ResolveVersion())
+	assert.Equal(t, "cat-3", tr.Version())
+	// A resolved version is not reserved again.
+	require.NoError(t, tr.ResolveVersion())
+	assert.Equal(t, "cat-3", tr.Version())
+
+	tr = NewTransform(testLogger(), NewMockCategoryStorer(t), Config{Version: "v1"})
+	require.NoError(t, tr.ResolveVersion())
+	assert.Equal(t, "v1", tr.Version())
+}
+
+func TestTransformResolveVersionConflict(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 5, 0, time.UTC)
+	tests := []struct {
+		name         string
+		version      string
+		mockBehavior func(storer *MockCategoryStorer)
+		want         string
+		wantErr      error
+	}{
+		{
+			name:    "Counter taken by a concurrent run",
+			version: "cat-{counter}",
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().VersionNames().Return([]string{"cat-1", "cat-2"}, nil).Once()
+				storer.EXPECT().ReserveVersion("cat-3").Return(false, nil)
+				storer.EXPECT().VersionNames().Return([]string{"cat-1", "cat-2", "cat-3"}, nil).Once()
+				storer.EXPECT().ReserveVersion("cat-4").Return(true, nil)
+			},
+			want: "cat-4",
+		},
+		{
+			name:    "Timestamp taken by a concurrent run",
+			version: "nightly-{timestamp}",
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().ReserveVersion("nightly-20240630T120005Z").Return(false, nil)
+				storer.EXPECT().ReserveVersion("nightly-20240630T120006Z").Return(true, nil)
+			},
+			want: "nightly-20240630T120006Z",
+		},
+		{
+			name:    "Semver always taken",
+			version: "m-v{semver:patch}",
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().VersionNames().Return([]string{"m-v1.0.0"}, nil).Times(maxReserveAttempts)
+				storer.EXPECT().ReserveVersion("m-v1.0.1").Return(false, nil).Times(maxReserveAttempts)
+			},
+			wantErr: ErrVersionNotReserved,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorer := NewMockCategoryStorer(t)
+			tt.mockBehavior(mockStorer)
+
+			tr := NewTransform(testLogger(), mockStorer, Config{Version: tt.version})
+			tr.now = func() time.Time { return now }
+			err := tr.ResolveVersion()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tr.Version())
+		})
+	}
+}
+
+func TestGenerateDatasetReleasesReservedVersion(t *testing.T) {
+	// The reserved version is removed again when the pipeline fails.
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().VersionNames().Return([]string{"cat-1"}, nil)
+	mockStorer.EXPECT().ReserveVersion("cat-2").Return(true, nil)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "cat-2", false, time.Duration(0)).Return(unlockNop, nil)
+	mockStorer.EXPECT().VersionInfo("cat-2").Return(VersionInfo{Version: "cat-2", Status: VersionStatusBuilding}, nil)
+	mockStorer.EXPECT().OriginalCategory().Return(Category{}, nil)
+	mockStorer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+	mockStorer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+	mockStorer.EXPECT().CategoryRemap().Return(nil, errors.New("category remap error"))
+	mockStorer.EXPECT().ReleaseVersion("cat-2").Return(nil)
+
+	tr := NewTransform(testLogger(), mockStorer, Config{Version: "cat-{counter}", TrainRatio: 100})
+	_, err := tr.GenerateDataset(context.Background())
+	assert.EqualError(t, err, "stage filter: category remap error")
+
+	// A version the run did not reserve is kept.
+	mockStorer = NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+	mockStorer.EXPECT().OriginalCategory().Return(nil, errors.New("original category error"))
+
+	_, err = NewTransform(testLogger(), mockStorer, Config{Version: "v1", TrainRatio: 100}).GenerateDataset(context.Background())
+	assert.EqualError(t, err, "original category error")
+}
@@ -0,0 +1,190 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+)
+
+// Stage names accepted in Config.Stages. Normalize, split, enrich and write are required.
+const (
+	StageFilter    = "filter"
+	StageNormalize = "normalize"
+	StageSample    = "sample"
+	StageDedupe    = "dedupe"
+	StageBalance   = "balance"
+	StageSplit     = "split"
+	StageEnrich    = "enrich"
+	StageWrite     = "write"
+)
+
+// requiredStages are the stages every pipeline runs, once and in this order.
+// Config.Stages may list them together with the optional stages, or only list the optional stages.
+var requiredStages = []string{StageNormalize, StageSplit, StageEnrich, StageWrite}
+
+// stagePhase orders the stages: filter works on the matched categories before normalize turns them into samples,
+// and sample, dedupe and balance refine the samples before they are split.
+var stagePhase = map[string]int{
+	StageFilter:    0,
+	StageNormalize: 1,
+	StageSample:    2,
+	StageDedupe:    2,
+	StageBalance:   2,
+	StageSplit:     3,
+	StageEnrich:    4,
+	StageWrite:     5,
+}
+
+// DefaultStages is the pipeline used when Config.Stages is empty.
+// It reproduces the classic behavior: remap and filter matched categories, resolve input levels,
+// optionally sample, shuffle and split, build dataset rows, and write them together with a taxonomy snapshot.
+var DefaultStages = []string{StageFilter, StageNormalize, StageSample, StageSplit, StageEnrich, StageWrite}
+
+var (
+	ErrUnknownStage  = errors.New("unknown pipeline stage")
+	ErrInvalidStages = errors.New("invalid pipeline stages")
+)
+
+// Sample is a matched category moving through the pipeline, together with its resolved input levels
+// and the split label assigned to it.
+type Sample struct {
+	Match  model.MatchCategory
+	Levels []string
+	Label  string
+}
+
+// State is the data passed from one Stage to the next.
+// Original, Tree and Matched are loaded before the pipeline runs; stages refine Samples and produce Dataset.
+// Tree is the category tree Original was loaded with and is stored as the taxonomy snapshot of the version.
+// Incremental reports that Matched only holds rows added since the last run (with an ID above AfterID),
+// and LastMatchID is the highest match_category ID loaded so far for the version.
+// Seed drives every random choice of the run. Deadline, when set, is when the run is cut off,
+// and Resume is the checkpoint of the interrupted run being resumed.
+// Partition is set when only one partition of a fan-out generation is generated.
+// Skipped, Warnings and Durations collect what is reported in the Result of the run.
+type State struct {
+	Version     string
+	Original    Category
+	Tree        []CategoryNode
+	Matched     []model.MatchCategory
+	Samples     []Sample
+	Dataset     []model.CategoryDataset
+	Incremental bool
+	AfterID     int32
+	LastMatchID int32
+	Seed        int64
+	Deadline    time.Time
+	Resume      *Checkpoint
+	Partition   *Partition
+	Actor       string
+
+	Skipped   map[string]int
+	Warnings  []string
+	Durations map[string]time.Duration
+}
+
+// Stage is a single, independently testable step of the dataset generation pipeline.
+type Stage interface {
+	Name() string
+	Run(st *State) error
+}
+
+// Pipeline is an ordered list of stages executed one after another on the same State.
+type Pipeline []Stage
+
+// Run executes every stage in order and stops at the first error, wrapping it with the stage name.
+// The duration of every stage is recorded in State.Durations under its name.
+func (p Pipeline) Run(log *slog.Logger, st *State) error {
+	for _, s := range p {
+		log.Debug("running stage", "stage", s.Name())
+		start := time.Now()
+		err := s.Run(st)
+		st.timed(s.Name(), start)
+		if err != nil {
+			return fmt.Errorf("stage %s: %w", s.Name(), err)
+		}
+	}
+	return nil
+}
+
+// stageRegistry maps stage names to constructors. New stages are added here.
+var stageRegistry = map[string]func(t *Transform) Stage{
+	StageFilter:    func(t *Transform) Stage { return &filterStage{log: t.log, catStore: t.catStore, config: t.config} },
+	StageNormalize: func(t *Transform) Stage { return &normalizeStage{log: t.log, config: t.config} },
+	StageSample:    func(t *Transform) Stage { return &sampleStage{log: t.log, config: t.config.Sample} },
+	StageDedupe:    func(t *Transform) Stage { return &dedupeStage{log: t.log} },
+	StageBalance:   func(t *Transform) Stage { return &balanceStage{log: t.log, maxPerCategory: t.config.MaxPerCategory} },
+	StageSplit:     func(t *Transform) Stage { return &splitStage{log: t.log, config: t.config} },
+	StageEnrich:    func(t *Transform) Stage { return &enrichStage{config: t.config} },
+	StageWrite:     func(t *Transform) Stage { return &writeStage{log: t.log, catStore: t.catStore} },
+}
+
+func (c Config) stages() []string {
+	if len(c.Stages) == 0 {
+		return DefaultStages
+	}
+	if slices.ContainsFunc(c.Stages, func(name string) bool { return slices.Contains(requiredStages, name) }) {
+		return c.Stages
+	}
+	// Only optional stages are listed: filter runs first, the others between normalize and split.
+	names := make([]string, 0, len(c.Stages)+len(requiredStages))
+	if slices.Contains(c.Stages, StageFilter) {
+		names = append(names, StageFilter)
+	}
+	names = append(names, StageNormalize)
+	for _, name := range c.Stages {
+		if name != StageFilter {
+			names = append(names, name)
+		}
+	}
+	return append(names, StageSplit, StageEnrich, StageWrite)
+}
+
+// validateStages checks that every stage is known and runs at most once, that the required stages
+// are all present, and that every stage runs in its phase (see stagePhase).
+func validateStages(names []string) error {
+	seen := make(map[string]struct{}, len(names))
+	phase := 0
+	for _, name := range names {
+		p, ok := stagePhase[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownStage, name)
+		}
+		if _, ok = seen[name]; ok {
+			return fmt.Errorf("%w: %s runs more than once", ErrInvalidStages, name)
+		}
+		if p < phase {
+			return fmt.Errorf("%w: %s runs out of order", ErrInvalidStages, name)
+		}
+		seen[name], phase = struct{}{}, p
+	}
+	for _, name := range requiredStages {
+		if _, ok := seen[name]; !ok {
+			return fmt.Errorf("%w: %s is required", ErrInvalidStages, name)
+		}
+	}
+	return nil
+}
+
+// resumePipeline builds the pipeline of a run resumed from a Checkpoint, which restores the samples
+// of the interrupted run and writes the rows it had not written yet.
+func (t *Transform) resumePipeline() Pipeline {
+	return Pipeline{&restoreStage{config: t.config}, stageRegistry[StageEnrich](t), stageRegistry[StageWrite](t)}
+}
+
+// Pipeline builds the pipeline described by the configuration.
+func (t *Transform) Pipeline() (Pipeline, error) {
+	names := t.config.stages()
+	if err := validateStages(names); err != nil {
+		return nil, err
+	}
+	p := make(Pipeline, 0, len(names))
+	for _, name := range names {
+		p = append(p, stageRegistry[name](t))
+	}
+	return p, nil
+}
@@ -0,0 +1,68 @@
+package transform
+
+import (
+	"slices"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+)
+
+// Remap maps an old category ID to the category ID that replaced it after a rename, merge or move.
+// Entries may be chained (1 -> 2 -> 3); Resolve follows the chain to the final category.
+type Remap map[int32]int32
+
+// Resolve follows the remap chain starting at id and returns the final category ID.
+// The second return value reports whether id was remapped at all.
+// A cycle in the mapping stops the walk at the last ID seen before repeating.
+func (r Remap) Resolve(id int32) (int32, bool) {
+	seen := map[int32]struct{}{id: {}}
+	cur := id
+	for {
+		next, ok := r[cur]
+		if !ok {
+			break
+		}
+		if _, loop := seen[next]; loop {
+			break
+		}
+		seen[next] = struct{}{}
+		cur = next
+	}
+	return cur, cur != id
+}
+
+// RemapReport summarizes how matched categories were adjusted to the current taxonomy.
+// Remapped counts rows whose MatchID was replaced through the Remap table,
+// Orphaned counts rows whose (remapped) MatchID no longer exists in the category tree;
+// those rows are left out of the dataset. OrphanedIDs lists the distinct missing category IDs.
+type RemapReport struct {
+	Remapped    int
+	Orphaned    int
+	OrphanedIDs []int32
+}
+
+// applyRemap rewrites MatchID of every matched category through the remap table and drops rows whose
+// category no longer exists in oCat. The input slice is not modified.
+func applyRemap(mCat []model.MatchCategory, remap Remap, oCat Category) ([]model.MatchCategory, RemapReport) {
+	var report RemapReport
+	orphaned := make(map[int32]struct{})
+	result := make([]model.MatchCategory, 0, len(mCat))
+	for _, v := range mCat {
+		id, remapped := remap.Resolve(*v.MatchID)
+		if remapped {
+			report.Remapped++
+			v.MatchID = &id
+		}
+		if _, ok := oCat[id]; !ok {
+			report.Orphaned++
+			orphaned[id] = struct{}{}
+			continue
+		}
+		result = append(result, v)
+	}
+
+	for id := range orphaned {
+		report.OrphanedIDs = append(report.OrphanedIDs, id)
+	}
+	slices.Sort(report.OrphanedIDs)
+	return result, report
+}
@@ -0,0 +1,53 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemapResolve(t *testing.T) {
+	tests := []struct {
+		name         string
+		remap        Remap
+		id           int32
+		want         int32
+		wantRemapped bool
+	}{
+		{name: "Not remapped", remap: Remap{1: 2}, id: 5, want: 5, wantRemapped: false},
+		{name: "Single step", remap: Remap{1: 2}, id: 1, want: 2, wantRemapped: true},
+		{name: "Chained", remap: Remap{1: 2, 2: 3}, id: 1, want: 3, wantRemapped: true},
+		{name: "Cycle", remap: Remap{1: 2, 2: 1}, id: 1, want: 2, wantRemapped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, remapped := tt.remap.Resolve(tt.id)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantRemapped, remapped)
+		})
+	}
+}
+
+func TestApplyRemap(t *testing.T) {
+	id := func(i int32) *int32 { return &i }
+	mCat := []model.MatchCategory{
+		{ID: 1, MatchID: id(10)},
+		{ID: 2, MatchID: id(11)},
+		{ID: 3, MatchID: id(12)},
+		{ID: 4, MatchID: id(13)},
+	}
+	oCat := Category{
+		10: {Name: "Kept"},
+		20: {Name: "Merged"},
+	}
+
+	got, report := applyRemap(mCat, Remap{11: 20, 12: 21}, oCat)
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, int32(10), *got[0].MatchID)
+	assert.Equal(t, int32(20), *got[1].MatchID)
+	assert.Equal(t, int32(11), *mCat[1].MatchID, "input must not be modified")
+	assert.Equal(t, RemapReport{Remapped: 2, Orphaned: 2, OrphanedIDs: []int32{13, 21}}, report)
+}
@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Reasons a matched category is left out of the dataset, the keys of Result.Skipped.
+const (
+	SkipOrphaned   = "orphaned"
+	SkipExcluded   = "excluded"
+	SkipInvalid    = "invalid_levels"
+	SkipShallow    = "too_shallow"
+	SkipTooDeep    = "too_deep"
+	SkipSampledOut = "sampled_out"
+	SkipDuplicate  = "duplicate"
+	SkipOverCap    = "over_category_cap"
+)
+
+// Phases of a run timed in Result.Durations, in addition to the name of every pipeline stage.
+// PhaseLoad covers locking the version and loading the original and matched categories,
+// and PhaseFinish the bookkeeping after the pipeline (checkpoint, partition and aliases).
+const (
+	PhaseLoad   = "load"
+	PhaseFinish = "finish"
+)
+
+// Result summarizes a GenerateDataset run: the rows written per split label, the matched categories
+// left out by reason, how long every phase took, the seed the run used and anything worth a second look.
+type Result struct {
+	Version     string                   `json:"version"`
+	Seed        int64                    `json:"seed"`
+	Incremental bool                     `json:"incremental"`
+	Matched     int                      `json:"matched"`
+	Counts      map[string]int           `json:"counts"`
+	Skipped     map[string]int           `json:"skipped,omitempty"`
+	Durations   map[string]time.Duration `json:"durations"`
+	Duration    time.Duration            `json:"duration"`
+	Warnings    []string                 `json:"warnings,omitempty"`
+}
+
+// Rows returns the total number of rows written.
+func (r Result) Rows() int {
+	n := 0
+	for _, c := range r.Counts {
+		n += c
+	}
+	return n
+}
+
+// LogValue logs the result as a group, with durations in milliseconds.
+func (r Result) LogValue() slog.Value {
+	durations := make([]slog.Attr, 0, len(r.Durations))
+	for phase, d := range r.Durations {
+		durations = append(durations, slog.Int64(phase, d.Milliseconds()))
+	}
+	return slog.GroupValue(
+		slog.String("version", r.Version),
+		slog.Int64("seed", r.Seed),
+		slog.Bool("incremental", r.Incremental),
+		slog.Int("matched", r.Matched),
+		slog.Int("rows", r.Rows()),
+		slog.Any("counts", r.Counts),
+		slog.Any("skipped", r.Skipped),
+		slog.Attr{Key: "durations_ms", Value: slog.GroupValue(durations...)},
+		slog.Int64("duration_ms", r.Duration.Milliseconds()),
+		slog.Any("warnings", r.Warnings),
+	)
+}
+
+// skip records that n matched categories were left out for the reason.
+func (st *State) skip(reason string, n int) {
+	if n <= 0 {
+		return
+	}
+	if st.Skipped == nil {
+		st.Skipped = make(map[string]int)
+	}
+	st.Skipped[reason] += n
+}
+
+// warn records a warning of the run.
+func (st *State) warn(msg string) {
+	st.Warnings = append(st.Warnings, msg)
+}
+
+// timed records how long the phase took since start.
+func (st *State) timed(phase string, start time.Time) {
+	if st.Durations == nil {
+		st.Durations = make(map[string]time.Duration)
+	}
+	st.Durations[phase] += time.Since(start)
+}
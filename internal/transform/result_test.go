@@ -0,0 +1,81 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDatasetResult(t *testing.T) {
+	matchID := func(i int32) *int32 { return &i }
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+	mockStorer.EXPECT().OriginalCategory().Return(Category{7: {Name: "Cat7", Path: "/cat7"}}, nil)
+	mockStorer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+	mockStorer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{
+		{ID: 1, L1: "Shop", MatchID: matchID(7)},
+		{ID: 2, L1: "shop", MatchID: matchID(7)},
+		{ID: 3, L1: "Shop", MatchID: matchID(9)},
+	}, nil)
+	mockStorer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+	mockStorer.EXPECT().CleanUp("v1").Return(nil)
+	mockStorer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+	mockStorer.EXPECT().SaveVersion("v1", int32(3), "").Return(nil)
+	mockStorer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(nil)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	r, err := NewTransform(logger, mockStorer, Config{
+		Version:    "v1",
+		TrainRatio: 100,
+		Seed:       42,
+		Stages:     []string{StageFilter, StageNormalize, StageDedupe, StageSplit, StageEnrich, StageWrite},
+	}).GenerateDataset(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1", r.Version)
+	assert.Equal(t, int64(42), r.Seed)
+	assert.Equal(t, 3, r.Matched)
+	assert.Equal(t, map[string]int{LabelTrain: 1}, r.Counts)
+	assert.Equal(t, 1, r.Rows())
+	assert.Equal(t, map[string]int{SkipOrphaned: 1, SkipDuplicate: 1}, r.Skipped)
+	assert.Len(t, r.Warnings, 1)
+	for _, phase := range []string{PhaseLoad, StageFilter, StageNormalize, StageDedupe, StageSplit, StageEnrich, StageWrite, PhaseFinish} {
+		assert.Contains(t, r.Durations, phase)
+	}
+
+	// The result is logged as the last record of the run.
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	var record struct {
+		Msg    string `json:"msg"`
+		Result struct {
+			Rows      int            `json:"rows"`
+			Skipped   map[string]int `json:"skipped"`
+			Durations map[string]int `json:"durations_ms"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(lines[len(lines)-1], &record))
+	assert.Equal(t, "dataset generation finished", record.Msg)
+	assert.Equal(t, 1, record.Result.Rows)
+	assert.Equal(t, r.Skipped, record.Result.Skipped)
+	assert.Contains(t, record.Result.Durations, StageWrite)
+}
+
+func TestGenerateDatasetResultOnError(t *testing.T) {
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(nil, ErrVersionLocked)
+
+	r, err := NewTransform(testLogger(), mockStorer, Config{Version: "v1"}).GenerateDataset(context.Background())
+	assert.ErrorIs(t, err, ErrVersionLocked)
+	assert.Equal(t, "v1", r.Version)
+	assert.Empty(t, r.Counts)
+}
@@ -0,0 +1,111 @@
+package transform
+
+import (
+	"cmp"
+	"errors"
+	"log/slog"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+var (
+	ErrInvalidSample = errors.New("invalid sample config: set exactly one of count or fraction (0 < fraction <= 1)")
+)
+
+// SampleConfig reduces the dataset to a small representative subset for quick experiments.
+// Exactly one of Count or Fraction must be set. Stratified keeps the proportion of every target
+// category (MatchID) and at least one sample per category, so Count is approximate in that mode.
+// Seed makes the selection reproducible; a zero Seed uses the seed of the run (Config.Seed).
+type SampleConfig struct {
+	Count      uint32  `json:"count"`
+	Fraction   float64 `json:"fraction"`
+	Stratified bool    `json:"stratified"`
+	Seed       int64   `json:"seed"`
+}
+
+func (s *SampleConfig) validate() error {
+	if s == nil {
+		return nil
+	}
+	hasCount := s.Count > 0
+	hasFraction := s.Fraction != 0
+	if hasCount == hasFraction || s.Fraction < 0 || s.Fraction > 1 {
+		return ErrInvalidSample
+	}
+	return nil
+}
+
+// sampleStage keeps a uniform or stratified random subset of the samples.
+// It is a no-op when no SampleConfig is configured. The relative order of the kept samples is preserved.
+type sampleStage struct {
+	log    *slog.Logger
+	config *SampleConfig
+}
+
+func (s *sampleStage) Name() string { return StageSample }
+
+func (s *sampleStage) Run(st *State) error {
+	if s.config == nil {
+		return nil
+	}
+	seed := cmp.Or(s.config.Seed, st.Seed)
+	//nolint:gosec // No need to use secure random number generator
+	rng := rand.New(rand.NewSource(seed))
+
+	total := len(st.Samples)
+	fraction := s.config.Fraction
+	if s.config.Count > 0 && total > 0 {
+		fraction = min(1, float64(s.config.Count)/float64(total))
+	}
+
+	var keep []int
+	if s.config.Stratified {
+		keep = stratifiedIndices(st.Samples, fraction, rng)
+	} else {
+		keep = uniformIndices(total, fraction, rng)
+	}
+
+	result := make([]Sample, 0, len(keep))
+	for _, i := range keep {
+		result = append(result, st.Samples[i])
+	}
+	s.log.Info("sampled dataset",
+		"before", total, "after", len(result), "stratified", s.config.Stratified, "seed", seed,
+	)
+	st.skip(SkipSampledOut, total-len(result))
+	st.Samples = result
+	return nil
+}
+
+// uniformIndices picks round(total*fraction) random indices, returned in ascending order.
+func uniformIndices(total int, fraction float64, rng *rand.Rand) []int {
+	n := int(math.Round(float64(total) * fraction))
+	keep := rng.Perm(total)[:n]
+	slices.Sort(keep)
+	return keep
+}
+
+// stratifiedIndices picks round(n*fraction) random indices per target category, at least one each,
+// returned in ascending order.
+func stratifiedIndices(samples []Sample, fraction float64, rng *rand.Rand) []int {
+	groups := make(map[int32][]int)
+	var order []int32
+	for i, v := range samples {
+		id := *v.Match.MatchID
+		if _, ok := groups[id]; !ok {
+			order = append(order, id)
+		}
+		groups[id] = append(groups[id], i)
+	}
+
+	var keep []int
+	for _, id := range order {
+		idx := groups[id]
+		n := max(1, int(math.Round(float64(len(idx))*fraction)))
+		rng.Shuffle(len(idx), func(i, j int) { idx[i], idx[j] = idx[j], idx[i] })
+		keep = append(keep, idx[:n]...)
+	}
+	slices.Sort(keep)
+	return keep
+}
@@ -0,0 +1,78 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleConfigValidate(t *testing.T) {
+	var none *SampleConfig
+	assert.NoError(t, none.validate())
+	assert.NoError(t, (&SampleConfig{Count: 10}).validate())
+	assert.NoError(t, (&SampleConfig{Fraction: 0.5}).validate())
+	assert.ErrorIs(t, (&SampleConfig{}).validate(), ErrInvalidSample)
+	assert.ErrorIs(t, (&SampleConfig{Count: 10, Fraction: 0.5}).validate(), ErrInvalidSample)
+	assert.ErrorIs(t, (&SampleConfig{Fraction: 1.5}).validate(), ErrInvalidSample)
+	assert.ErrorIs(t, Config{Sample: &SampleConfig{}}.Validate(), ErrInvalidSample)
+}
+
+func TestSampleStage(t *testing.T) {
+	// 80 samples of category 1 and 20 samples of category 2.
+	newState := func() *State {
+		st := &State{}
+		for i := range 100 {
+			id := int32(1)
+			if i >= 80 {
+				id = 2
+			}
+			st.Samples = append(st.Samples, Sample{Match: model.MatchCategory{ID: int32(i), MatchID: matchID(id)}})
+		}
+		return st
+	}
+	countByCategory := func(samples []Sample) map[int32]int {
+		counts := make(map[int32]int)
+		for _, v := range samples {
+			counts[*v.Match.MatchID]++
+		}
+		return counts
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		st := newState()
+		require.NoError(t, (&sampleStage{log: testLogger()}).Run(st))
+		assert.Len(t, st.Samples, 100)
+	})
+
+	t.Run("Uniform count is seeded", func(t *testing.T) {
+		first, second := newState(), newState()
+		cfg := &SampleConfig{Count: 10, Seed: 42}
+		require.NoError(t, (&sampleStage{log: testLogger(), config: cfg}).Run(first))
+		require.NoError(t, (&sampleStage{log: testLogger(), config: cfg}).Run(second))
+		assert.Len(t, first.Samples, 10)
+		assert.Equal(t, first.Samples, second.Samples)
+		assert.IsIncreasing(t, func() []int32 {
+			ids := make([]int32, 0, len(first.Samples))
+			for _, v := range first.Samples {
+				ids = append(ids, v.Match.ID)
+			}
+			return ids
+		}())
+	})
+
+	t.Run("Stratified fraction", func(t *testing.T) {
+		st := newState()
+		cfg := &SampleConfig{Fraction: 0.1, Stratified: true, Seed: 7}
+		require.NoError(t, (&sampleStage{log: testLogger(), config: cfg}).Run(st))
+		assert.Equal(t, map[int32]int{1: 8, 2: 2}, countByCategory(st.Samples))
+	})
+
+	t.Run("Stratified keeps small categories", func(t *testing.T) {
+		st := newState()
+		cfg := &SampleConfig{Fraction: 0.01, Stratified: true, Seed: 7}
+		require.NoError(t, (&sampleStage{log: testLogger(), config: cfg}).Run(st))
+		assert.Equal(t, map[int32]int{1: 1, 2: 1}, countByCategory(st.Samples))
+	})
+}
@@ -0,0 +1,335 @@
+package transform
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+)
+
+// Split labels assigned by the split stage.
+const (
+	LabelTrain    = "train"
+	LabelValidate = "validate"
+	LabelTest     = "test"
+)
+
+// filterStage remaps matched categories whose target category was renamed, merged or moved
+// and drops the ones whose category no longer exists or lies outside the configured category subtrees.
+type filterStage struct {
+	log      *slog.Logger
+	catStore CategoryStorer
+	config   Config
+}
+
+func (s *filterStage) Name() string { return StageFilter }
+
+func (s *filterStage) Run(st *State) error {
+	remap, err := s.catStore.CategoryRemap()
+	if err != nil {
+		return err
+	}
+	var report RemapReport
+	st.Matched, report = applyRemap(st.Matched, remap, st.Original)
+	s.log.Info("remapped matched category",
+		"remapped", report.Remapped, "orphaned", report.Orphaned, "orphaned_ids", report.OrphanedIDs,
+	)
+	st.skip(SkipOrphaned, report.Orphaned)
+	if report.Orphaned > 0 {
+		st.warn(fmt.Sprintf("%d matched categories point to deleted categories", report.Orphaned))
+	}
+
+	if len(s.config.IncludeCategories) == 0 && len(s.config.ExcludeCategories) == 0 {
+		return nil
+	}
+	// Subtrees are filtered after remapping so that remapped rows are judged by their new category.
+	f := newCategoryFilter(st.Tree, s.config.IncludeCategories, s.config.ExcludeCategories)
+	result := st.Matched[:0]
+	for _, v := range st.Matched {
+		if f.keep(*v.MatchID) {
+			result = append(result, v)
+		}
+	}
+	s.log.Info("filtered category subtrees", "removed", len(st.Matched)-len(result))
+	st.skip(SkipExcluded, len(st.Matched)-len(result))
+	st.Matched = result
+	return nil
+}
+
+// normalizeStage turns matched categories into samples by resolving their input levels
+// and applying the configured maximum depth and truncate policy.
+type normalizeStage struct {
+	log    *slog.Logger
+	config Config
+}
+
+func (s *normalizeStage) Name() string { return StageNormalize }
+
+func (s *normalizeStage) Run(st *State) error {
+	var report DepthReport
+	maxDepth := s.config.maxLevels()
+	policy := s.config.truncatePolicy()
+	st.Samples = make([]Sample, 0, len(st.Matched))
+	for _, v := range st.Matched {
+		levels, err := inputLevels(v)
+		if err != nil {
+			s.log.Warn("invalid input levels", "match_category_id", v.ID, "error", err)
+			report.Invalid++
+			continue
+		}
+		report.MaxDepth = max(report.MaxDepth, len(levels))
+		if len(levels) < int(s.config.MinInputDepth) {
+			report.Shallow++
+			continue
+		}
+
+		levels, truncated, drop := truncateLevels(levels, maxDepth, policy)
+		if drop {
+			report.Dropped++
+			continue
+		}
+		if truncated {
+			report.Truncated++
+		}
+		st.Samples = append(st.Samples, Sample{Match: v, Levels: levels})
+	}
+	s.log.Info("resolved input levels",
+		"truncated", report.Truncated, "dropped", report.Dropped, "invalid", report.Invalid,
+		"shallow", report.Shallow, "max_depth", report.MaxDepth,
+	)
+	st.skip(SkipInvalid, report.Invalid)
+	st.skip(SkipShallow, report.Shallow)
+	st.skip(SkipTooDeep, report.Dropped)
+	if report.Invalid > 0 {
+		st.warn(fmt.Sprintf("%d matched categories have invalid input levels", report.Invalid))
+	}
+	return nil
+}
+
+// dedupeStage removes samples with the same input levels and target category, keeping the first one.
+type dedupeStage struct {
+	log *slog.Logger
+}
+
+func (s *dedupeStage) Name() string { return StageDedupe }
+
+func (s *dedupeStage) Run(st *State) error {
+	type key struct {
+		levels  string
+		matchID int32
+	}
+	seen := make(map[key]struct{}, len(st.Samples))
+	result := st.Samples[:0]
+	for _, v := range st.Samples {
+		k := key{levels: strings.ToLower(strings.Join(v.Levels, "\x00")), matchID: *v.Match.MatchID}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		result = append(result, v)
+	}
+	s.log.Info("deduplicated samples", "removed", len(st.Samples)-len(result))
+	st.skip(SkipDuplicate, len(st.Samples)-len(result))
+	st.Samples = result
+	return nil
+}
+
+// balanceStage caps the number of samples per target category so that large categories
+// do not dominate the dataset. A maxPerCategory of 0 disables the cap.
+type balanceStage struct {
+	log            *slog.Logger
+	maxPerCategory uint32
+}
+
+func (s *balanceStage) Name() string { return StageBalance }
+
+func (s *balanceStage) Run(st *State) error {
+	if s.maxPerCategory == 0 {
+		return nil
+	}
+	counts := make(map[int32]uint32)
+	result := st.Samples[:0]
+	for _, v := range st.Samples {
+		id := *v.Match.MatchID
+		if counts[id] >= s.maxPerCategory {
+			continue
+		}
+		counts[id]++
+		result = append(result, v)
+	}
+	s.log.Info("balanced samples", "removed", len(st.Samples)-len(result), "max_per_category", s.maxPerCategory)
+	st.skip(SkipOverCap, len(st.Samples)-len(result))
+	st.Samples = result
+	return nil
+}
+
+// splitStage optionally shuffles the samples and assigns train, validate and test labels
+// according to the configured ratios.
+type splitStage struct {
+	log    *slog.Logger
+	config Config
+}
+
+func (s *splitStage) Name() string { return StageSplit }
+
+func (s *splitStage) Run(st *State) error {
+	if s.config.Shuffle {
+		s.log.Info("shuffle matched category", "seed", st.Seed)
+
+		src := rand.NewSource(st.Seed)
+		//nolint:gosec // No need to use secure random number generator
+		rng := rand.New(src)
+		rng.Shuffle(len(st.Samples), func(i, j int) {
+			st.Samples[i], st.Samples[j] = st.Samples[j], st.Samples[i]
+		})
+	}
+
+	const percentage = 100
+
+	// Calculate the number of samples for each label
+	totalSamples := len(st.Samples)
+	numTrain := int(float64(totalSamples) * (float64(s.config.TrainRatio) / percentage))
+	numValidate := int(float64(totalSamples) * (float64(s.config.ValidateRatio) / percentage))
+
+	for i := range st.Samples {
+		switch {
+		case i < numTrain:
+			st.Samples[i].Label = LabelTrain
+		case i < numTrain+numValidate:
+			st.Samples[i].Label = LabelValidate
+		default:
+			st.Samples[i].Label = LabelTest
+		}
+	}
+	return nil
+}
+
+// enrichStage builds the dataset rows from the samples, adding the output labels,
+// category IDs, input levels in the configured shape and the joined input text.
+type enrichStage struct {
+	config Config
+}
+
+func (s *enrichStage) Name() string { return StageEnrich }
+
+func (s *enrichStage) Run(st *State) error {
+	st.Dataset = make([]model.CategoryDataset, 0, len(st.Samples))
+	for _, v := range st.Samples {
+		matchCategoryID := v.Match.ID
+		row := model.CategoryDataset{
+			FullPathOut:     st.Original[*v.Match.MatchID].Path,
+			NameOut:         st.Original[*v.Match.MatchID].Name,
+			Version:         st.Version,
+			Label:           v.Label,
+			CategoryIDOut:   *v.Match.MatchID,
+			MatchCategoryID: &matchCategoryID,
+			InputText:       inputText(v.Levels, s.config.textSeparator(), s.config.TextLevelMarkers),
+		}
+		if err := setLevels(&row, v.Levels, s.config.pathShape()); err != nil {
+			return err
+		}
+		st.Dataset = append(st.Dataset, row)
+	}
+	return nil
+}
+
+// writeStage replaces the dataset of the version in the CategoryStorer (or appends to it in incremental mode),
+// records the last processed match_category ID, and stores a snapshot of the whole category tree
+// so the label space can be reconstructed later.
+// Rows are inserted in batches; when the run's deadline is near, the progress is saved as a Checkpoint
+// and the stage stops with ErrDeadlineReached. A resumed run only removes the rows after the checkpoint,
+// which a failed continuation may have inserted, and skips the rows already written and the snapshot,
+// which was stored with the first checkpoint.
+// A partition run only replaces the rows of its partition and leaves the version to Transform.Finalize.
+type writeStage struct {
+	log       *slog.Logger
+	catStore  CategoryStorer
+	batchSize int
+}
+
+func (s *writeStage) Name() string { return StageWrite }
+
+func (s *writeStage) Run(st *State) error {
+	written := 0
+	switch {
+	case st.Partition != nil:
+		if err := s.catStore.CleanUpPartition(st.Version, *st.Partition); err != nil {
+			return err
+		}
+	case st.Resume != nil:
+		written = min(st.Resume.RowsWritten, len(st.Dataset))
+		// A continuation that failed after the last checkpoint may have inserted some of the remaining rows.
+		ids := make([]int32, 0, len(st.Dataset)-written)
+		for _, v := range st.Dataset[written:] {
+			ids = append(ids, *v.MatchCategoryID)
+		}
+		if err := s.catStore.CleanUpRows(st.Version, ids); err != nil {
+			return err
+		}
+	case !st.Incremental:
+		if err := s.catStore.CleanUp(st.Version); err != nil {
+			return err
+		}
+		s.log.Info("cleaned up dataset", "version", st.Version)
+	}
+
+	batchSize := cmp.Or(s.batchSize, insertBatchSize)
+	for start := written; ; start += batchSize {
+		// At least one batch is written per run, so that every continuation makes progress.
+		if start > written && st.deadlineNear(time.Now()) {
+			return s.checkpoint(st, start)
+		}
+		end := min(start+batchSize, len(st.Dataset))
+		if err := s.catStore.InsertDataset(st.Dataset[start:end]); err != nil {
+			return err
+		}
+		if end >= len(st.Dataset) {
+			break
+		}
+	}
+	s.log.Info("inserted dataset",
+		"version", st.Version, "rows", len(st.Dataset)-written, "incremental", st.Incremental, "resumed", st.Resume != nil,
+	)
+	// The version of a fan-out generation is completed by Transform.Finalize.
+	if st.Partition != nil {
+		return nil
+	}
+
+	if err := s.catStore.SaveVersion(st.Version, st.LastMatchID, st.Actor); err != nil {
+		return err
+	}
+	// A resumed run keeps the snapshot stored with its first checkpoint.
+	if st.Resume != nil {
+		return nil
+	}
+	return s.saveSnapshot(st)
+}
+
+// saveSnapshot stores the category tree loaded at the start of the run as the taxonomy snapshot of the version.
+func (s *writeStage) saveSnapshot(st *State) error {
+	if err := s.catStore.SaveSnapshot(st.Version, st.Tree); err != nil {
+		return err
+	}
+	s.log.Info("saved taxonomy snapshot", "version", st.Version, "categories", len(st.Tree))
+	return nil
+}
+
+// checkpoint saves the progress of the run after the first written rows.
+// The first checkpoint of a run also stores the taxonomy snapshot, so that it shows the categories
+// the rows were built from rather than the ones current when the last continuation finishes.
+func (s *writeStage) checkpoint(st *State, written int) error {
+	if st.Resume == nil {
+		if err := s.saveSnapshot(st); err != nil {
+			return err
+		}
+	}
+	if err := s.catStore.SaveCheckpoint(newCheckpoint(st, written)); err != nil {
+		return err
+	}
+	s.log.Info("saved checkpoint", "version", st.Version, "rows_written", written, "rows", len(st.Dataset))
+	return fmt.Errorf("%w: %d of %d rows written", ErrDeadlineReached, written, len(st.Dataset))
+}
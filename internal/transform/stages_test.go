@@ -0,0 +1,312 @@
+package transform
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func matchID(i int32) *int32 { return &i }
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+func TestFilterStage(t *testing.T) {
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().CategoryRemap().Return(Remap{1: 2}, nil)
+
+	st := &State{
+		Original: Category{2: {Name: "Cat2"}},
+		Matched: []model.MatchCategory{
+			{ID: 1, MatchID: matchID(1)},
+			{ID: 2, MatchID: matchID(3)},
+		},
+	}
+	s := &filterStage{log: testLogger(), catStore: mockStorer}
+	require.NoError(t, s.Run(st))
+	require.Len(t, st.Matched, 1)
+	assert.Equal(t, int32(2), *st.Matched[0].MatchID)
+}
+
+func TestFilterStageSubtree(t *testing.T) {
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+
+	st := &State{
+		Original: Category{2: {Name: "Cat2"}, 3: {Name: "Cat3"}},
+		Tree:     []CategoryNode{{ID: 1}, {ID: 2, ParentID: matchID(1)}, {ID: 3}},
+		Matched: []model.MatchCategory{
+			{ID: 1, MatchID: matchID(2)},
+			{ID: 2, MatchID: matchID(3)},
+		},
+	}
+	s := &filterStage{log: testLogger(), catStore: mockStorer, config: Config{IncludeCategories: []int32{1}}}
+	require.NoError(t, s.Run(st))
+	require.Len(t, st.Matched, 1)
+	assert.Equal(t, int32(1), st.Matched[0].ID)
+}
+
+func TestNormalizeStage(t *testing.T) {
+	str := func(s string) *string { return &s }
+	st := &State{
+		Matched: []model.MatchCategory{
+			{ID: 1, L1: "A", L2: str("B"), MatchID: matchID(1)},
+			{ID: 2, L1: "A", Levels: str(`broken`), MatchID: matchID(1)},
+			{ID: 3, L1: "A", Levels: str(`["A","B","C"]`), MatchID: matchID(1)},
+		},
+	}
+	s := &normalizeStage{log: testLogger(), config: Config{MaxDepth: 2, TruncatePolicy: TruncateDrop}}
+	require.NoError(t, s.Run(st))
+	require.Len(t, st.Samples, 1)
+	assert.Equal(t, []string{"A", "B"}, st.Samples[0].Levels)
+
+	s = &normalizeStage{log: testLogger(), config: Config{MinInputDepth: 3}}
+	require.NoError(t, s.Run(st))
+	require.Len(t, st.Samples, 1)
+	assert.Equal(t, int32(3), st.Samples[0].Match.ID)
+
+	// The both shape holds 8 levels in its columns, so deeper paths go through the truncate policy.
+	deep := &State{Matched: []model.MatchCategory{
+		{ID: 4, L1: "A", Levels: str(`["A","B","C","D","E","F","G","H","I"]`), MatchID: matchID(1)},
+	}}
+	s = &normalizeStage{log: testLogger(), config: Config{PathShape: PathShapeBoth, TruncatePolicy: TruncateDrop}}
+	require.NoError(t, s.Run(deep))
+	assert.Empty(t, deep.Samples)
+	assert.Equal(t, 1, deep.Skipped[SkipTooDeep])
+
+	s = &normalizeStage{log: testLogger(), config: Config{PathShape: PathShapeJSON, TruncatePolicy: TruncateDrop}}
+	require.NoError(t, s.Run(deep))
+	require.Len(t, deep.Samples, 1)
+	assert.Len(t, deep.Samples[0].Levels, 9)
+}
+
+func TestDedupeStage(t *testing.T) {
+	st := &State{
+		Samples: []Sample{
+			{Match: model.MatchCategory{ID: 1, MatchID: matchID(1)}, Levels: []string{"A", "B"}},
+			{Match: model.MatchCategory{ID: 2, MatchID: matchID(1)}, Levels: []string{"a", "b"}},
+			{Match: model.MatchCategory{ID: 3, MatchID: matchID(2)}, Levels: []string{"A", "B"}},
+		},
+	}
+	s := &dedupeStage{log: testLogger()}
+	require.NoError(t, s.Run(st))
+	require.Len(t, st.Samples, 2)
+	assert.Equal(t, int32(1), st.Samples[0].Match.ID)
+	assert.Equal(t, int32(3), st.Samples[1].Match.ID)
+}
+
+func TestBalanceStage(t *testing.T) {
+	newState := func() *State {
+		return &State{
+			Samples: []Sample{
+				{Match: model.MatchCategory{ID: 1, MatchID: matchID(1)}},
+				{Match: model.MatchCategory{ID: 2, MatchID: matchID(1)}},
+				{Match: model.MatchCategory{ID: 3, MatchID: matchID(1)}},
+				{Match: model.MatchCategory{ID: 4, MatchID: matchID(2)}},
+			},
+		}
+	}
+
+	st := newState()
+	require.NoError(t, (&balanceStage{log: testLogger(), maxPerCategory: 2}).Run(st))
+	assert.Len(t, st.Samples, 3)
+
+	st = newState()
+	require.NoError(t, (&balanceStage{log: testLogger()}).Run(st))
+	assert.Len(t, st.Samples, 4)
+}
+
+func TestSplitStage(t *testing.T) {
+	st := &State{Samples: make([]Sample, 10)}
+	s := &splitStage{log: testLogger(), config: Config{TrainRatio: 60, ValidateRatio: 20, TestRatio: 20}}
+	require.NoError(t, s.Run(st))
+
+	counts := make(map[string]int)
+	for _, v := range st.Samples {
+		counts[v.Label]++
+	}
+	assert.Equal(t, map[string]int{LabelTrain: 6, LabelValidate: 2, LabelTest: 2}, counts)
+}
+
+func TestWriteStage(t *testing.T) {
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().CleanUp("v1").Return(nil)
+	mockStorer.EXPECT().InsertDataset([]model.CategoryDataset{{Version: "v1"}}).Return(nil)
+	mockStorer.EXPECT().SaveVersion("v1", int32(5), "alice").Return(nil)
+	mockStorer.EXPECT().SaveSnapshot("v1", []CategoryNode{{ID: 7}}).Return(errors.New("save snapshot error"))
+
+	st := &State{
+		Version: "v1", Tree: []CategoryNode{{ID: 7}}, Dataset: []model.CategoryDataset{{Version: "v1"}}, LastMatchID: 5, Actor: "alice",
+	}
+	s := &writeStage{log: testLogger(), catStore: mockStorer}
+	assert.EqualError(t, s.Run(st), "save snapshot error")
+}
+
+func TestWriteStageIncremental(t *testing.T) {
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().InsertDataset([]model.CategoryDataset{{Version: "v1"}}).Return(nil)
+	mockStorer.EXPECT().SaveVersion("v1", int32(9), "").Return(nil)
+	mockStorer.EXPECT().SaveSnapshot("v1", []CategoryNode{{ID: 7}}).Return(nil)
+
+	st := &State{
+		Version: "v1", Tree: []CategoryNode{{ID: 7}}, Dataset: []model.CategoryDataset{{Version: "v1"}}, Incremental: true, LastMatchID: 9,
+	}
+	s := &writeStage{log: testLogger(), catStore: mockStorer}
+	assert.NoError(t, s.Run(st))
+}
+
+func TestWriteStageDeadline(t *testing.T) {
+	dataset := []model.CategoryDataset{
+		{MatchCategoryID: matchID(4), CategoryIDOut: 7, Label: LabelTrain},
+		{MatchCategoryID: matchID(6), CategoryIDOut: 8, Label: LabelTrain},
+		{MatchCategoryID: matchID(5), CategoryIDOut: 7, Label: LabelTest},
+	}
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().CleanUp("v1").Return(nil)
+	mockStorer.EXPECT().InsertDataset(dataset[:2]).Return(nil)
+	mockStorer.EXPECT().SaveSnapshot("v1", []CategoryNode{{ID: 7}}).Return(nil)
+	mockStorer.EXPECT().SaveCheckpoint(Checkpoint{
+		Version: "v1", Seed: 7, AfterID: 3, UpToID: 9, RowsWritten: 2,
+		Assignments: []Assignment{
+			{MatchCategoryID: 4, CategoryID: 7, Label: LabelTrain},
+			{MatchCategoryID: 6, CategoryID: 8, Label: LabelTrain},
+			{MatchCategoryID: 5, CategoryID: 7, Label: LabelTest},
+		},
+		Categories: Category{7: {Name: "Cat7"}, 8: {Name: "Cat8"}},
+	}).Return(nil)
+
+	st := &State{
+		Version: "v1", Dataset: dataset, AfterID: 3, LastMatchID: 9, Seed: 7,
+		Original: Category{7: {Name: "Cat7"}, 8: {Name: "Cat8"}, 9: {Name: "Cat9"}},
+		Tree:     []CategoryNode{{ID: 7}},
+		Deadline: time.Now().Add(DeadlineMargin / 2),
+	}
+	s := &writeStage{log: testLogger(), catStore: mockStorer, batchSize: 2}
+	assert.ErrorIs(t, s.Run(st), ErrDeadlineReached)
+}
+
+func TestWriteStageResume(t *testing.T) {
+	dataset := make([]model.CategoryDataset, 5)
+	for i := range dataset {
+		id := int32(i + 1)
+		dataset[i] = model.CategoryDataset{ID: id, MatchCategoryID: &id}
+	}
+	mockStorer := NewMockCategoryStorer(t)
+	newState := func() *State {
+		return &State{
+			Version: "v1", Dataset: dataset, LastMatchID: 9,
+			Resume: &Checkpoint{Version: "v1", RowsWritten: 2},
+		}
+	}
+	s := &writeStage{log: testLogger(), catStore: mockStorer, batchSize: 2}
+
+	// The continuation fails after inserting a batch beyond the checkpoint.
+	mockStorer.EXPECT().CleanUpRows("v1", []int32{3, 4, 5}).Return(nil).Twice()
+	mockStorer.EXPECT().InsertDataset(dataset[2:4]).Return(nil).Twice()
+	mockStorer.EXPECT().InsertDataset(dataset[4:]).Return(errors.New("insert error")).Once()
+	assert.EqualError(t, s.Run(newState()), "insert error")
+
+	// Its retry removes that batch before inserting the remaining rows again.
+	mockStorer.EXPECT().InsertDataset(dataset[4:]).Return(nil).Once()
+	mockStorer.EXPECT().SaveVersion("v1", int32(9), "").Return(nil)
+	assert.NoError(t, s.Run(newState()))
+}
+
+func TestSplitStageSeed(t *testing.T) {
+	labels := func() []string {
+		st := &State{Samples: make([]Sample, 20), Seed: 42}
+		for i := range st.Samples {
+			st.Samples[i].Match.ID = int32(i)
+		}
+		s := &splitStage{log: testLogger(), config: Config{Shuffle: true, TrainRatio: 60, ValidateRatio: 20, TestRatio: 20}}
+		require.NoError(t, s.Run(st))
+		out := make([]string, 0, len(st.Samples))
+		for _, v := range st.Samples {
+			out = append(out, strconv.Itoa(int(v.Match.ID))+":"+v.Label)
+		}
+		return out
+	}
+	assert.Equal(t, labels(), labels())
+}
+
+func TestPipelineStages(t *testing.T) {
+	tests := []struct {
+		name    string
+		stages  []string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "Full list",
+			stages: []string{StageFilter, StageNormalize, StageDedupe, StageBalance, StageSplit, StageEnrich, StageWrite},
+			want:   []string{StageFilter, StageNormalize, StageDedupe, StageBalance, StageSplit, StageEnrich, StageWrite},
+		},
+		{
+			name:   "Only optional stages",
+			stages: []string{StageBalance, StageFilter, StageSample},
+			want:   []string{StageFilter, StageNormalize, StageBalance, StageSample, StageSplit, StageEnrich, StageWrite},
+		},
+		{name: "Write only", stages: []string{StageWrite}, wantErr: ErrInvalidStages},
+		{name: "Without split", stages: []string{StageNormalize, StageEnrich, StageWrite}, wantErr: ErrInvalidStages},
+		{
+			name:    "Out of order",
+			stages:  []string{StageWrite, StageSplit, StageNormalize, StageEnrich},
+			wantErr: ErrInvalidStages,
+		},
+		{
+			name:    "Filter after normalize",
+			stages:  []string{StageNormalize, StageFilter, StageSplit, StageEnrich, StageWrite},
+			wantErr: ErrInvalidStages,
+		},
+		{
+			name:    "Twice",
+			stages:  []string{StageNormalize, StageSplit, StageEnrich, StageWrite, StageWrite},
+			wantErr: ErrInvalidStages,
+		},
+		{name: "Unknown", stages: []string{StageSplit, "magic"}, wantErr: ErrUnknownStage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{Version: "v1", TrainRatio: 100, Stages: tt.stages}
+			p, err := NewTransform(testLogger(), NewMockCategoryStorer(t), cfg).Pipeline()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, cfg.Validate(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			names := make([]string, 0, len(p))
+			for _, s := range p {
+				names = append(names, s.Name())
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	_, err := NewTransform(testLogger(), NewMockCategoryStorer(t), Config{Stages: []string{StageSplit, "magic"}}).Pipeline()
+	assert.ErrorIs(t, err, ErrUnknownStage)
+
+	p, err := NewTransform(testLogger(), NewMockCategoryStorer(t), Config{}).Pipeline()
+	require.NoError(t, err)
+	names := make([]string, 0, len(p))
+	for _, s := range p {
+		names = append(names, s.Name())
+	}
+	assert.Equal(t, DefaultStages, names)
+
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().CategoryRemap().Return(nil, errors.New("category remap error"))
+	p = Pipeline{&filterStage{log: testLogger(), catStore: mockStorer}}
+	assert.EqualError(t, p.Run(testLogger(), &State{}), "stage filter: category remap error")
+}
@@ -0,0 +1,108 @@
+package transform
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// CategoryNode represents a single node of the category tree, including intermediate categories.
+// A list of CategoryNode is stored as the taxonomy snapshot of a dataset version,
+// which makes it possible to reconstruct the label space a model was trained on.
+type CategoryNode struct {
+	ID       int32  `json:"id"`
+	Name     string `json:"name"`
+	ParentID *int32 `json:"parent_id"`
+	HasChild bool   `json:"has_child"`
+	Path     string `json:"path"`
+}
+
+// CategoryChange describes a category that exists in both trees but whose name, parent or path differs.
+type CategoryChange struct {
+	Before CategoryNode `json:"before"`
+	After  CategoryNode `json:"after"`
+}
+
+// TaxonomyDiff is the result of comparing a taxonomy snapshot against another category tree.
+// Added holds categories only present in the current tree, Removed holds categories only present
+// in the snapshot, and Changed holds categories present in both with a different name, parent or path.
+// All lists are ordered by category ID.
+type TaxonomyDiff struct {
+	Added   []CategoryNode   `json:"added"`
+	Removed []CategoryNode   `json:"removed"`
+	Changed []CategoryChange `json:"changed"`
+}
+
+// Empty reports whether the two compared trees are identical.
+func (d TaxonomyDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// CompareTaxonomy compares a taxonomy snapshot with the current category tree, matching nodes by category ID.
+func CompareTaxonomy(snapshot, current []CategoryNode) TaxonomyDiff {
+	before := make(map[int32]CategoryNode, len(snapshot))
+	for _, n := range snapshot {
+		before[n.ID] = n
+	}
+
+	var diff TaxonomyDiff
+	seen := make(map[int32]struct{}, len(current))
+	for _, n := range current {
+		seen[n.ID] = struct{}{}
+		old, ok := before[n.ID]
+		if !ok {
+			diff.Added = append(diff.Added, n)
+			continue
+		}
+		if old.Name != n.Name || old.Path != n.Path || !sameParent(old.ParentID, n.ParentID) {
+			diff.Changed = append(diff.Changed, CategoryChange{Before: old, After: n})
+		}
+	}
+	for _, n := range snapshot {
+		if _, ok := seen[n.ID]; !ok {
+			diff.Removed = append(diff.Removed, n)
+		}
+	}
+
+	byID := func(a, b CategoryNode) int { return cmp.Compare(a.ID, b.ID) }
+	slices.SortFunc(diff.Added, byID)
+	slices.SortFunc(diff.Removed, byID)
+	slices.SortFunc(diff.Changed, func(a, b CategoryChange) int { return cmp.Compare(a.After.ID, b.After.ID) })
+	return diff
+}
+
+func sameParent(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Snapshot returns the taxonomy snapshot stored for the given version.
+// Returns ErrSnapshotNotFound if no snapshot was stored for the version.
+func (t *Transform) Snapshot(version string) ([]CategoryNode, error) {
+	snapshot, err := t.catStore.Snapshot(version)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshot) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, version)
+	}
+	return snapshot, nil
+}
+
+// CompareSnapshot loads the taxonomy snapshot stored for the given version and compares it
+// with the current category tree from the CategoryStorer.
+// Returns ErrSnapshotNotFound if no snapshot was stored for the version.
+func (t *Transform) CompareSnapshot(version string) (TaxonomyDiff, error) {
+	snapshot, err := t.Snapshot(version)
+	if err != nil {
+		return TaxonomyDiff{}, err
+	}
+
+	current, err := t.catStore.CategoryTree()
+	if err != nil {
+		return TaxonomyDiff{}, err
+	}
+	return CompareTaxonomy(snapshot, current), nil
+}
@@ -0,0 +1,104 @@
+package transform
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareTaxonomy(t *testing.T) {
+	root := int32(1)
+	other := int32(2)
+	snapshot := []CategoryNode{
+		{ID: 1, Name: "Electronics", HasChild: true, Path: "Electronics"},
+		{ID: 2, Name: "Home", HasChild: true, Path: "Home"},
+		{ID: 3, Name: "Phones", ParentID: &root, Path: "Electronics > Phones"},
+		{ID: 4, Name: "Lamps", ParentID: &other, Path: "Home > Lamps"},
+	}
+	current := []CategoryNode{
+		{ID: 1, Name: "Electronics", HasChild: true, Path: "Electronics"},
+		{ID: 2, Name: "Home", HasChild: true, Path: "Home"},
+		{ID: 3, Name: "Mobile Phones", ParentID: &root, Path: "Electronics > Mobile Phones"},
+		{ID: 5, Name: "Rugs", ParentID: &other, Path: "Home > Rugs"},
+	}
+
+	diff := CompareTaxonomy(snapshot, current)
+
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []CategoryNode{current[3]}, diff.Added)
+	assert.Equal(t, []CategoryNode{snapshot[3]}, diff.Removed)
+	assert.Equal(t, []CategoryChange{{Before: snapshot[2], After: current[2]}}, diff.Changed)
+	assert.True(t, CompareTaxonomy(snapshot, snapshot).Empty())
+}
+
+func TestCompareSnapshot(t *testing.T) {
+	tree := []CategoryNode{{ID: 1, Name: "Cat1", Path: "Cat1"}}
+	type mockBehavior func(storer *MockCategoryStorer)
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantErr      error
+		wantEmpty    bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().Snapshot("v1").Return(tree, nil)
+				storer.EXPECT().CategoryTree().Return(tree, nil)
+			},
+			wantEmpty: true,
+		},
+		{
+			name: "Snapshot not found",
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().Snapshot("v1").Return([]CategoryNode{}, nil)
+			},
+			wantErr: ErrSnapshotNotFound,
+		},
+		{
+			name: "Failed to get category tree",
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().Snapshot("v1").Return(tree, nil)
+				storer.EXPECT().CategoryTree().Return(nil, errors.New("category tree error"))
+			},
+			wantErr: errors.New("category tree error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+			mockStorer := NewMockCategoryStorer(t)
+			tt.mockBehavior(mockStorer)
+
+			tr := NewTransform(logger, mockStorer, Config{})
+			diff, err := tr.CompareSnapshot("v1")
+
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEmpty, diff.Empty())
+		})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	tree := []CategoryNode{{ID: 1, Name: "Cat1", Path: "Cat1"}}
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().Snapshot("v1").Return(tree, nil)
+	mockStorer.EXPECT().Snapshot("v2").Return(nil, nil)
+	tr := NewTransform(testLogger(), mockStorer, Config{})
+
+	got, err := tr.Snapshot("v1")
+	assert.NoError(t, err)
+	assert.Equal(t, tree, got)
+
+	_, err = tr.Snapshot("v2")
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+}
@@ -0,0 +1,33 @@
+package transform
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultTextSeparator joins input levels into the InputText column when no separator is configured.
+// It matches the separator used for category paths.
+const DefaultTextSeparator = " > "
+
+// inputText joins the input levels into a single string for text models, skipping empty levels.
+// When markers is true each level is prefixed with its position, e.g. "L1: Shop > L2: Phones".
+func inputText(levels []string, sep string, markers bool) string {
+	parts := make([]string, 0, len(levels))
+	for i, l := range levels {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		if markers {
+			l = "L" + strconv.Itoa(i+1) + ": " + l
+		}
+		parts = append(parts, l)
+	}
+	return strings.Join(parts, sep)
+}
+
+func (c Config) textSeparator() string {
+	if c.TextSeparator == "" {
+		return DefaultTextSeparator
+	}
+	return c.TextSeparator
+}
@@ -0,0 +1,28 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputText(t *testing.T) {
+	tests := []struct {
+		name    string
+		levels  []string
+		sep     string
+		markers bool
+		want    string
+	}{
+		{name: "Default separator", levels: []string{"Shop", "Phones"}, sep: DefaultTextSeparator, want: "Shop > Phones"},
+		{name: "Custom separator", levels: []string{"Shop", "Phones", "Android"}, sep: " | ", want: "Shop | Phones | Android"},
+		{name: "Skip empty levels", levels: []string{"Shop", " ", "Android"}, sep: " ", want: "Shop Android"},
+		{name: "Level markers", levels: []string{"Shop", "Phones"}, sep: " ", markers: true, want: "L1: Shop L2: Phones"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, inputText(tt.levels, tt.sep, tt.markers))
+		})
+	}
+}
@@ -6,40 +6,131 @@
 package transform
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
-	"math/rand"
 	"time"
 
 	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
 )
 
-// Config holds the configuration parameters for the transformation process.
-// It includes settings for the dataset version, whether to shuffle the data for randomness,
-// and the ratios for splitting the data into train, validate, and test sets,
-// which are crucial for model training and evaluation.
+var (
+	ErrSnapshotNotFound = errors.New("taxonomy snapshot not found")
+)
+
+// Config holds the configuration parameters for the transformation process, one field per option of a generate message.
+// Version may be a template (see VersionTokenTimestamp) and Partition is only set by FanOut.
 type Config struct {
-	Version       string `json:"version"`
-	Shuffle       bool   `json:"shuffle"`
-	TrainRatio    uint8  `json:"train_ratio"`
-	ValidateRatio uint8  `json:"validate_ratio"`
-	TestRatio     uint8  `json:"test_ratio"`
+	Version          string   `json:"version"`
+	Shuffle          bool     `json:"shuffle"`
+	TrainRatio       uint8    `json:"train_ratio"`
+	ValidateRatio    uint8    `json:"validate_ratio"`
+	TestRatio        uint8    `json:"test_ratio"`
+	PathShape        string   `json:"path_shape"`
+	MaxDepth         uint8    `json:"max_depth"`
+	TruncatePolicy   string   `json:"truncate_policy"`
+	TextSeparator    string   `json:"text_separator"`
+	TextLevelMarkers bool     `json:"text_level_markers"`
+	Stages           []string `json:"stages"`
+	MaxPerCategory   uint32   `json:"max_per_category"`
+
+	IncludeCategories []int32  `json:"include_categories"`
+	ExcludeCategories []int32  `json:"exclude_categories"`
+	AllowL1           []string `json:"allow_l1"`
+	DenyL1            []string `json:"deny_l1"`
+	MinInputDepth     uint8    `json:"min_input_depth"`
+
+	Sample      *SampleConfig `json:"sample"`
+	Incremental bool          `json:"incremental"`
+	Force       bool          `json:"force"`
+	Aliases     []string      `json:"aliases"`
+
+	LockWaitSeconds uint32 `json:"lock_wait_seconds"`
+	Seed            int64  `json:"seed"`
+	Resume          bool   `json:"resume"`
+	Actor           string `json:"actor"`
+
+	Partition *Partition `json:"partition,omitempty"`
+}
+
+// Validate checks that the configuration only uses known options.
+func (c Config) Validate() error {
+	switch c.pathShape() {
+	case PathShapeColumns, PathShapeJSON, PathShapeBoth:
+	default:
+		return ErrInvalidPathShape
+	}
+	if c.pathShape() != PathShapeJSON && c.MaxDepth > columnLevels {
+		return ErrInvalidMaxDepth
+	}
+	switch c.truncatePolicy() {
+	case TruncateKeepFirst, TruncateKeepLast, TruncateDrop:
+	default:
+		return ErrInvalidTruncatePolicy
+	}
+	if _, err := parseVersionTemplate(c.Version); err != nil {
+		return err
+	}
+	if err := c.Sample.validate(); err != nil {
+		return err
+	}
+	if len(c.Aliases) > 0 {
+		if err := validateAliases(c.Aliases); err != nil {
+			return err
+		}
+	}
+	if c.Partition != nil && (c.Incremental || c.Resume) {
+		return fmt.Errorf("%w: a partition cannot be incremental or resumed", ErrInvalidFanOut)
+	}
+	if c.Partition != nil {
+		if err := c.validateFanOut(); err != nil {
+			return err
+		}
+	}
+	return validateStages(c.stages())
 }
 
 // CategoryStorer defines the interface for storing and retrieving category data used in the machine learning pipeline.
 // It provides methods for accessing original and matched categories,
 // cleaning up old datasets based on version, and inserting new datasets, ensuring data consistency and version control.
+// It also persists and reads back the taxonomy snapshot taken for each dataset version
+// and moves the aliases of a version once it has been generated.
+// Fan-out generations (see FanOut) record their partitions and complete them one by one.
 type CategoryStorer interface {
 	OriginalCategory() (Category, error)
-	MatchedCategory() ([]model.MatchCategory, error)
+	MatchedCategory(filter MatchFilter) ([]model.MatchCategory, error)
 	CleanUp(version string) error
+	CleanUpRows(version string, matchCategoryIDs []int32) error
 	InsertDataset(dataset []model.CategoryDataset) error
+	CategoryTree() ([]CategoryNode, error)
+	SaveSnapshot(version string, tree []CategoryNode) error
+	Snapshot(version string) ([]CategoryNode, error)
+	CategoryRemap() (Remap, error)
+	VersionInfo(version string) (VersionInfo, error)
+	SaveVersion(version string, lastMatchID int32, actor string) error
+	VersionNames() ([]string, error)
+	ReserveVersion(version string) (bool, error)
+	ReleaseVersion(version string) error
+	SaveCheckpoint(cp Checkpoint) error
+	Checkpoint(version string) (Checkpoint, error)
+	DeleteCheckpoint(version string) error
+	LockVersion(ctx context.Context, version string, shared bool, wait time.Duration) (func() error, error)
+	MoveAliases(version string, names []string) error
+	SetVersionStatus(version, status string) error
+	MatchIDRange(filter MatchFilter) (first, last int32, err error)
+	SavePartitions(fo FanOutInfo) error
+	FanOutInfo(id string) (FanOutInfo, error)
+	CleanUpPartition(version string, p Partition) error
+	FinishPartition(version string, index, rows int) (pending int, err error)
+	Partitions(version string) ([]PartitionInfo, error)
 }
 
 // CategoryDeepest represents the deepest level of a category, containing its name and its full hierarchical path.
 // This information can be used as labels or features in machine learning models.
 type CategoryDeepest struct {
-	Name string
-	Path string
+	Name string `json:"name"`
+	Path string `json:"path"`
 }
 
 // Category represents a mapping of category IDs to their corresponding CategoryDeepest information.
@@ -50,6 +141,10 @@ type Transform struct {
 	log      *slog.Logger
 	catStore CategoryStorer
 	config   Config
+	now      func() time.Time
+
+	fanInReady bool
+	reserved   bool
 }
 
 // NewTransform creates a new Transform instance, responsible for orchestrating the dataset generation process.
@@ -60,86 +155,243 @@ func NewTransform(l *slog.Logger, cs CategoryStorer, cfg Config) *Transform {
 		log:      l.With("component", "transform"),
 		catStore: cs,
 		config:   cfg,
+		now:      time.Now,
 	}
 }
 
-// GenerateDataset generates a dataset specifically designed for training and evaluating machine learning models.
-// It retrieves original and matched categories from the CategoryStorer,
-// optionally shuffles the matched categories to ensure a random distribution of data,
-// splits the data into train, validate, and test sets according to the configured ratios,
-// which is crucial for model training and performance assessment,
-// cleans up previous datasets with the same version from the CategoryStorer to avoid data conflicts,
-// and inserts the newly generated dataset into the CategoryStorer.
-// The resulting dataset contains features (L1-L8) and labels (FullPathOut, NameOut)
-// that can be used to train a model to predict category paths or names.
-// Returns an error if any of the steps fail, using specific error variables for clarity.
-func (t *Transform) GenerateDataset() error {
-	oCat, err := t.catStore.OriginalCategory()
+// GenerateDataset runs the pipeline configured by Config.Stages for Config.Version under the version lock and writes the rows.
+// The returned Result summarizes the run, also when it fails; ErrDeadlineReached means it was checkpointed (see Continuation).
+func (t *Transform) GenerateDataset(ctx context.Context) (Result, error) {
+	start := time.Now()
+	var r Result
+	err := t.generate(ctx, &r)
+	// A checkpointed run keeps its reservation for the continuation.
+	if err != nil && !errors.Is(err, ErrDeadlineReached) {
+		t.releaseVersion()
+	}
+	r.Version = t.config.Version
+	r.Duration = time.Since(start)
+
+	switch {
+	case err == nil:
+		t.log.Info("dataset generation finished", "result", r)
+	case errors.Is(err, ErrDeadlineReached):
+		t.log.Warn("dataset generation stopped", "result", r, "error", err)
+	default:
+		t.log.Error("dataset generation failed", "result", r, "error", err)
+	}
+	return r, err
+}
+
+// generate runs GenerateDataset, filling r as it goes.
+func (t *Transform) generate(ctx context.Context, r *Result) error {
+	st := &State{}
+	defer func() {
+		r.Seed, r.Incremental = st.Seed, st.Incremental
+		r.Skipped, r.Warnings, r.Durations = st.Skipped, st.Warnings, st.Durations
+	}()
+	loadStart := time.Now()
+
+	if err := t.config.Validate(); err != nil {
+		return err
+	}
+	if err := t.ResolveVersion(); err != nil {
+		return err
+	}
+	pipeline, err := t.Pipeline()
 	if err != nil {
 		return err
 	}
-	t.log.Info("get all original category")
 
-	mCat, err := t.catStore.MatchedCategory()
+	// Partitions share the lock of their version, so that they run in parallel but never alongside a full run,
+	// and each one takes the lock of its partition.
+	if t.config.Partition != nil {
+		unlockVersion, lErr := t.lock(ctx, t.config.Version, true)
+		if lErr != nil {
+			return lErr
+		}
+		defer unlockVersion()
+		unlock, lErr := t.lock(ctx, PartitionLockKey(t.config.Version, t.config.Partition.Index), false)
+		if lErr != nil {
+			return lErr
+		}
+		defer unlock()
+	} else {
+		unlock, lErr := t.lock(ctx, t.config.Version, false)
+		if lErr != nil {
+			return lErr
+		}
+		defer unlock()
+	}
+
+	info, exists, err := t.checkWritable()
 	if err != nil {
 		return err
 	}
-	t.log.Info("get all matched category")
+	// A building version that this run did not reserve belongs to a fan-out generation or another run,
+	// whose rows a full run would remove.
+	if exists && info.Status == VersionStatusBuilding && !t.reserved &&
+		t.config.Partition == nil && !t.config.Resume {
+		return fmt.Errorf("%w: %s", ErrVersionBuilding, t.config.Version)
+	}
+	if exists && info.Immutable {
+		st.warn("overwrote immutable version " + t.config.Version)
+	}
 
-	if t.config.Shuffle {
-		t.log.Info("shuffle matched category")
+	filter := t.config.matchFilter()
+	incremental := false
+	seed := t.config.Seed
+	var resume *Checkpoint
+	switch {
+	case t.config.Partition != nil:
+		filter.AfterID = t.config.Partition.AfterID
+		filter.UpToID = t.config.Partition.UpToID
+		t.log.Info("generating partition", "version", t.config.Version, "partition", t.config.Partition.Index,
+			"after_id", filter.AfterID, "up_to_id", filter.UpToID,
+		)
+	case t.config.Resume:
+		cp, cErr := t.catStore.Checkpoint(t.config.Version)
+		if cErr != nil {
+			return cErr
+		}
+		resume = &cp
+		pipeline = t.resumePipeline()
+		incremental = cp.Incremental
+		filter.AfterID = cp.AfterID
+		filter.UpToID = cp.UpToID
+		seed = cp.Seed
+		t.log.Info("resuming from checkpoint", "version", t.config.Version, "rows_written", cp.RowsWritten)
+	case t.config.Incremental:
+		if exists {
+			incremental = true
+			filter.AfterID = info.LastMatchID
+		} else {
+			t.log.Info("no previous run, generating full dataset", "version", t.config.Version)
+			st.warn("incremental run without a previous run generated the full dataset")
+		}
+	}
+	if seed == 0 {
+		seed = t.now().UnixNano()
+	}
+	t.config.Seed = seed
 
-		src := rand.NewSource(time.Now().UnixNano())
-		//nolint:gosec // No need to use secure random number generator
-		rng := rand.New(src)
-		rng.Shuffle(len(mCat), func(i, j int) {
-			mCat[i], mCat[j] = mCat[j], mCat[i]
-		})
+	// A resumed run builds its rows from the categories recorded in the checkpoint.
+	var oCat Category
+	var tree []CategoryNode
+	if resume == nil {
+		if oCat, err = t.catStore.OriginalCategory(); err != nil {
+			return err
+		}
+		if tree, err = t.catStore.CategoryTree(); err != nil {
+			return err
+		}
+		t.log.Info("get all original category", "categories", len(tree))
 	}
 
-	const percentage = 100
+	mCat, err := t.catStore.MatchedCategory(filter)
+	if err != nil {
+		return err
+	}
+	t.log.Info("get all matched category", "incremental", incremental, "after_id", filter.AfterID, "seed", seed)
 
-	// Calculate the number of samples for each label
-	totalSamples := len(mCat)
-	numTrain := int(float64(totalSamples) * (float64(t.config.TrainRatio) / percentage))
-	numValidate := int(float64(totalSamples) * (float64(t.config.ValidateRatio) / percentage))
+	st.Version = t.config.Version
+	st.Original, st.Tree, st.Matched = oCat, tree, mCat
+	st.Incremental = incremental
+	st.AfterID, st.LastMatchID = filter.AfterID, filter.AfterID
+	st.Seed = seed
+	st.Resume = resume
+	st.Partition = t.config.Partition
+	st.Actor = t.config.Actor
+	r.Matched = len(mCat)
+	// Partitions are not checkpointed: a partition that runs out of time is retried as a whole.
+	if deadline, ok := ctx.Deadline(); ok && st.Partition == nil {
+		st.Deadline = deadline
+	}
+	for _, v := range mCat {
+		st.LastMatchID = max(st.LastMatchID, v.ID)
+	}
+	st.timed(PhaseLoad, loadStart)
+	if err = pipeline.Run(t.log, st); err != nil {
+		return err
+	}
+	r.Counts = make(map[string]int)
+	for _, v := range st.Dataset {
+		r.Counts[v.Label]++
+	}
+	if len(st.Dataset) == 0 {
+		st.warn("no rows were generated")
+	}
 
-	var dataset []model.CategoryDataset
-	var label string
-	for i, v := range mCat {
-		switch {
-		case i < numTrain:
-			label = "train"
-		case i < numTrain+numValidate:
-			label = "validate"
-		default:
-			label = "test"
+	finishStart := time.Now()
+	defer st.timed(PhaseFinish, finishStart)
+	if resume != nil {
+		if err = t.catStore.DeleteCheckpoint(t.config.Version); err != nil {
+			return err
 		}
+	}
 
-		dataset = append(dataset, model.CategoryDataset{
-			L1In:        v.L1,
-			L2In:        v.L2,
-			L3In:        v.L3,
-			L4In:        v.L4,
-			L5In:        v.L5,
-			L6In:        v.L6,
-			L7In:        v.L7,
-			L8In:        v.L8,
-			FullPathOut: oCat[*v.MatchID].Path,
-			NameOut:     oCat[*v.MatchID].Name,
-			Version:     t.config.Version,
-			Label:       label,
-		})
-	}
-
-	if err = t.catStore.CleanUp(t.config.Version); err != nil {
-		return err
+	if p := t.config.Partition; p != nil {
+		pending, pErr := t.catStore.FinishPartition(t.config.Version, p.Index, len(st.Dataset))
+		if pErr != nil {
+			return pErr
+		}
+		t.fanInReady = pending == 0
+		t.log.Info("finished partition", "version", t.config.Version, "partition", p.Index, "pending", pending)
+		return nil
 	}
-	t.log.Info("cleaned up dataset", "version", t.config.Version)
 
-	if err = t.catStore.InsertDataset(dataset); err != nil {
+	if len(t.config.Aliases) == 0 {
+		return nil
+	}
+	if err = t.catStore.MoveAliases(t.config.Version, t.config.Aliases); err != nil {
 		return err
 	}
-	t.log.Info("inserted dataset", "version", t.config.Version)
+	t.log.Info("moved aliases", "aliases", t.config.Aliases, "version", t.config.Version)
 	return nil
 }
+
+// releaseVersion removes the version reserved by ResolveVersion after the run failed,
+// so that failed runs do not leave empty versions behind. Versions this run did not reserve are kept.
+func (t *Transform) releaseVersion() {
+	if !t.reserved {
+		return
+	}
+	if err := t.catStore.ReleaseVersion(t.config.Version); err != nil {
+		t.log.Warn("failed to release reserved version", "version", t.config.Version, "error", err)
+		return
+	}
+	t.reserved = false
+	t.log.Info("released reserved version", "version", t.config.Version)
+}
+
+// lock takes the exclusive or shared lock on key (a version or PartitionLockKey),
+// waiting up to Config.LockWaitSeconds or until ctx is done. The returned function releases it.
+func (t *Transform) lock(ctx context.Context, key string, shared bool) (func(), error) {
+	wait := time.Duration(t.config.LockWaitSeconds) * time.Second
+	unlock, err := t.catStore.LockVersion(ctx, key, shared, wait)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, key)
+	}
+	return func() {
+		if uErr := unlock(); uErr != nil {
+			t.log.Warn("failed to unlock version", "version", key, "error", uErr)
+		}
+	}, nil
+}
+
+// checkWritable loads the bookkeeping of the version and returns ErrVersionImmutable
+// if it is immutable and Config.Force is not set. exists is false for versions that were never generated.
+func (t *Transform) checkWritable() (info VersionInfo, exists bool, err error) {
+	info, err = t.catStore.VersionInfo(t.config.Version)
+	exists = err == nil
+	if err != nil && !errors.Is(err, ErrVersionNotFound) {
+		return VersionInfo{}, false, err
+	}
+	if exists && info.Immutable {
+		if !t.config.Force {
+			return VersionInfo{}, false, fmt.Errorf("%w: %s", ErrVersionImmutable, t.config.Version)
+		}
+		t.log.Warn("overwriting immutable version", "version", t.config.Version)
+	}
+	return info, exists, nil
+}
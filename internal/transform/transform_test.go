@@ -1,16 +1,22 @@
 package transform
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"os"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+func unlockNop() error { return nil }
+
 func TestGenerateDataset(t *testing.T) {
 	type mockBehavior func(storer *MockCategoryStorer)
 	tests := []struct {
@@ -29,10 +35,16 @@ func TestGenerateDataset(t *testing.T) {
 				TestRatio:     20,
 			},
 			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
 				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
-				storer.EXPECT().MatchedCategory().Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
 				storer.EXPECT().CleanUp("v1").Return(nil)
 				storer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+				storer.EXPECT().SaveVersion("v1", mock.AnythingOfType("int32"), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -46,16 +58,243 @@ func TestGenerateDataset(t *testing.T) {
 				TestRatio:     20,
 			},
 			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
 				storer.EXPECT().OriginalCategory().Return(Category{
 					1: {Name: "Cat1", Path: "/cat1"},
 					2: {Name: "Cat2", Path: "/cat2"},
 				}, nil)
-				storer.EXPECT().MatchedCategory().Return([]model.MatchCategory{
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{
 					{MatchID: func() *int32 { i := int32(1); return &i }()},
 					{MatchID: func() *int32 { i := int32(2); return &i }()},
 				}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+				storer.EXPECT().CleanUp("v1").Return(nil)
+				storer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+				storer.EXPECT().SaveVersion("v1", mock.AnythingOfType("int32"), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Success carries category IDs",
+			cfg: Config{
+				Version:       "v1",
+				Shuffle:       false,
+				TrainRatio:    100,
+				ValidateRatio: 0,
+				TestRatio:     0,
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().OriginalCategory().Return(Category{
+					7: {Name: "Cat7", Path: "/cat7"},
+				}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{{ID: 7, Name: "Cat7", Path: "Cat7"}}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{
+					{ID: 42, L1: "Shop", MatchID: func() *int32 { i := int32(7); return &i }()},
+				}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+				storer.EXPECT().CleanUp("v1").Return(nil)
+				storer.EXPECT().InsertDataset(mock.MatchedBy(func(ds []model.CategoryDataset) bool {
+					return len(ds) == 1 &&
+						ds[0].CategoryIDOut == 7 &&
+						ds[0].MatchCategoryID != nil && *ds[0].MatchCategoryID == 42 &&
+						ds[0].NameOut == "Cat7" &&
+						ds[0].InputText == "Shop"
+				})).Return(nil)
+				storer.EXPECT().SaveVersion("v1", mock.AnythingOfType("int32"), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{{ID: 7, Name: "Cat7", Path: "Cat7"}}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid config",
+			cfg: Config{
+				Version:   "v1",
+				PathShape: "xml",
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {},
+			wantErr:      true,
+		},
+		{
+			name: "Success with L1 filter",
+			cfg: Config{
+				Version: "v1",
+				AllowL1: []string{"Shop"},
+				DenyL1:  []string{"Outlet"},
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{AllowL1: []string{"Shop"}, DenyL1: []string{"Outlet"}}).
+					Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+				storer.EXPECT().CleanUp("v1").Return(nil)
+				storer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+				storer.EXPECT().SaveVersion("v1", mock.AnythingOfType("int32"), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Success incremental",
+			cfg: Config{
+				Version:     "v1",
+				TrainRatio:  100,
+				Incremental: true,
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().OriginalCategory().Return(Category{
+					7: {Name: "Cat7", Path: "/cat7"},
+				}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1", LastMatchID: 10}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{AfterID: 10}).Return([]model.MatchCategory{
+					{ID: 11, L1: "Shop", MatchID: func() *int32 { i := int32(7); return &i }()},
+				}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+				storer.EXPECT().InsertDataset(mock.MatchedBy(func(ds []model.CategoryDataset) bool {
+					return len(ds) == 1 && *ds[0].MatchCategoryID == 11
+				})).Return(nil)
+				storer.EXPECT().SaveVersion("v1", int32(11), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Success incremental without previous run",
+			cfg: Config{
+				Version:     "v1",
+				Incremental: true,
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+				storer.EXPECT().CleanUp("v1").Return(nil)
+				storer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+				storer.EXPECT().SaveVersion("v1", int32(0), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed to get version info",
+			cfg: Config{
+				Version:     "v1",
+				Incremental: true,
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, errors.New("version info error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Success moves aliases",
+			cfg: Config{
+				Version: "v1",
+				Aliases: []string{AliasLatest},
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
 				storer.EXPECT().CleanUp("v1").Return(nil)
 				storer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+				storer.EXPECT().SaveVersion("v1", int32(0), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(nil)
+				storer.EXPECT().MoveAliases("v1", []string{AliasLatest}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed run keeps aliases",
+			cfg: Config{
+				Version: "v1",
+				Aliases: []string{AliasLatest},
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+				storer.EXPECT().CleanUp("v1").Return(errors.New("cleanup error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid aliases",
+			cfg: Config{
+				Version: "v1",
+				Aliases: []string{""},
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {},
+			wantErr:      true,
+		},
+		{
+			name: "Failed to resolve version template",
+			cfg: Config{
+				Version: "v{counter}",
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().VersionNames().Return(nil, errors.New("version names error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Version locked",
+			cfg: Config{
+				Version:         "v1",
+				LockWaitSeconds: 2,
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, 2*time.Second).Return(nil, ErrVersionLocked)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Immutable version",
+			cfg: Config{
+				Version: "v1",
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1", Immutable: true}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Success overwriting immutable version with force",
+			cfg: Config{
+				Version: "v1",
+				Force:   true,
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1", Immutable: true}, nil)
+				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+				storer.EXPECT().CleanUp("v1").Return(nil)
+				storer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+				storer.EXPECT().SaveVersion("v1", int32(0), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -65,6 +304,8 @@ func TestGenerateDataset(t *testing.T) {
 				Version: "v1",
 			},
 			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
 				storer.EXPECT().OriginalCategory().Return(nil, errors.New("original category error"))
 			},
 			wantErr: true,
@@ -75,8 +316,56 @@ func TestGenerateDataset(t *testing.T) {
 				Version: "v1",
 			},
 			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return(nil, errors.New("matched category error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Success with remap",
+			cfg: Config{
+				Version:       "v1",
+				Shuffle:       false,
+				TrainRatio:    100,
+				ValidateRatio: 0,
+				TestRatio:     0,
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().OriginalCategory().Return(Category{
+					8: {Name: "Cat8", Path: "/cat8"},
+				}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{
+					{ID: 1, MatchID: func() *int32 { i := int32(7); return &i }()},
+					{ID: 2, MatchID: func() *int32 { i := int32(9); return &i }()},
+				}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{7: 8}, nil)
+				storer.EXPECT().CleanUp("v1").Return(nil)
+				storer.EXPECT().InsertDataset(mock.MatchedBy(func(ds []model.CategoryDataset) bool {
+					return len(ds) == 1 && ds[0].CategoryIDOut == 8 && ds[0].NameOut == "Cat8"
+				})).Return(nil)
+				storer.EXPECT().SaveVersion("v1", mock.AnythingOfType("int32"), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed to get category remap",
+			cfg: Config{
+				Version: "v1",
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
 				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
-				storer.EXPECT().MatchedCategory().Return(nil, errors.New("matched category error"))
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(nil, errors.New("category remap error"))
 			},
 			wantErr: true,
 		},
@@ -86,8 +375,12 @@ func TestGenerateDataset(t *testing.T) {
 				Version: "v1",
 			},
 			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
 				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
-				storer.EXPECT().MatchedCategory().Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
 				storer.EXPECT().CleanUp("v1").Return(errors.New("cleanup error"))
 			},
 			wantErr: true,
@@ -98,13 +391,80 @@ func TestGenerateDataset(t *testing.T) {
 				Version: "v1",
 			},
 			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
 				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
-				storer.EXPECT().MatchedCategory().Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
 				storer.EXPECT().CleanUp("v1").Return(nil)
 				storer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(errors.New("insert dataset error"))
 			},
 			wantErr: true,
 		},
+		{
+			name: "Failed to get category tree",
+			cfg: Config{
+				Version: "v1",
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
+				storer.EXPECT().CategoryTree().Return(nil, errors.New("category tree error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Resume from checkpoint",
+			cfg: Config{
+				Version: "v1",
+				Resume:  true,
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().Checkpoint("v1").Return(Checkpoint{Version: "v1", Seed: 7, AfterID: 3, UpToID: 9, RowsWritten: 0}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{AfterID: 3, UpToID: 9}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CleanUpRows("v1", []int32{}).Return(nil)
+				storer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+				storer.EXPECT().SaveVersion("v1", int32(3), "").Return(nil)
+				storer.EXPECT().DeleteCheckpoint("v1").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Resume without checkpoint",
+			cfg: Config{
+				Version: "v1",
+				Resume:  true,
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().Checkpoint("v1").Return(Checkpoint{}, ErrCheckpointNotFound)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Failed to save snapshot",
+			cfg: Config{
+				Version: "v1",
+			},
+			mockBehavior: func(storer *MockCategoryStorer) {
+				storer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+				storer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+				storer.EXPECT().OriginalCategory().Return(Category{}, nil)
+				storer.EXPECT().CategoryTree().Return([]CategoryNode{}, nil)
+				storer.EXPECT().MatchedCategory(MatchFilter{}).Return([]model.MatchCategory{}, nil)
+				storer.EXPECT().CategoryRemap().Return(Remap{}, nil)
+				storer.EXPECT().CleanUp("v1").Return(nil)
+				storer.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+				storer.EXPECT().SaveVersion("v1", mock.AnythingOfType("int32"), "").Return(nil)
+				storer.EXPECT().SaveSnapshot("v1", []CategoryNode{}).Return(errors.New("snapshot error"))
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -116,7 +476,7 @@ func TestGenerateDataset(t *testing.T) {
 			tt.mockBehavior(mockStorer)
 
 			tr := NewTransform(logger, mockStorer, tt.cfg)
-			err := tr.GenerateDataset()
+			_, err := tr.GenerateDataset(context.Background())
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -126,3 +486,90 @@ func TestGenerateDataset(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateDatasetImmutable(t *testing.T) {
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1", Immutable: true}, nil)
+
+	_, err := NewTransform(testLogger(), mockStorer, Config{Version: "v1", Incremental: true}).GenerateDataset(context.Background())
+	assert.ErrorIs(t, err, ErrVersionImmutable)
+}
+
+func TestGenerateDatasetLock(t *testing.T) {
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(nil, ErrVersionLocked)
+	_, err := NewTransform(testLogger(), mockStorer, Config{Version: "v1"}).GenerateDataset(context.Background())
+	assert.ErrorIs(t, err, ErrVersionLocked)
+
+	unlocked := false
+	mockStorer = NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(func() error { unlocked = true; return nil }, nil)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{}, errors.New("version info error"))
+	_, err = NewTransform(testLogger(), mockStorer, Config{Version: "v1"}).GenerateDataset(context.Background())
+	assert.EqualError(t, err, "version info error")
+	assert.True(t, unlocked)
+}
+
+func TestGenerateDatasetBuilding(t *testing.T) {
+	// The version is being built by a fan-out generation, whose rows a full run would remove.
+	mockStorer := NewMockCategoryStorer(t)
+	mockStorer.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+	mockStorer.EXPECT().VersionInfo("v1").Return(VersionInfo{Version: "v1", Status: VersionStatusBuilding}, nil)
+
+	_, err := NewTransform(testLogger(), mockStorer, Config{Version: "v1", TrainRatio: 100}).GenerateDataset(context.Background())
+	assert.ErrorIs(t, err, ErrVersionBuilding)
+}
+
+func TestGenerateDatasetResumeOrder(t *testing.T) {
+	str := func(s string) *string { return &s }
+	cfg := Config{Version: "v1", Seed: 7, Shuffle: true, TrainRatio: 50, ValidateRatio: 25, TestRatio: 25}
+	matched := []model.MatchCategory{
+		{ID: 1, L1: "Shop", L2: str("Phones"), MatchID: matchID(7)},
+		{ID: 2, L1: "Shop", L2: str("Tablets"), MatchID: matchID(8)},
+		{ID: 3, L1: "Shop", L2: str("Laptops"), MatchID: matchID(7)},
+		{ID: 4, L1: "Home", L2: str("Chairs"), MatchID: matchID(8)},
+		{ID: 5, L1: "Home", L2: str("Tables"), MatchID: matchID(7)},
+		{ID: 6, L1: "Home", L2: str("Lamps"), MatchID: matchID(8)},
+	}
+
+	// The interrupted run writes two rows and checkpoints the rest.
+	var cp Checkpoint
+	first := NewMockCategoryStorer(t)
+	first.EXPECT().CategoryRemap().Return(Remap{}, nil)
+	first.EXPECT().CleanUp("v1").Return(nil)
+	first.EXPECT().InsertDataset(mock.AnythingOfType("[]model.CategoryDataset")).Return(nil)
+	first.EXPECT().SaveSnapshot("v1", []CategoryNode{{ID: 7}, {ID: 8}}).Return(nil)
+	first.EXPECT().SaveCheckpoint(mock.AnythingOfType("Checkpoint")).Run(func(c Checkpoint) { cp = c }).Return(nil)
+
+	tr := NewTransform(testLogger(), first, cfg)
+	pipeline, err := tr.Pipeline()
+	require.NoError(t, err)
+	pipeline[len(pipeline)-1] = &writeStage{log: testLogger(), catStore: first, batchSize: 2}
+	st := &State{
+		Version: "v1", Seed: 7, LastMatchID: 6, Matched: slices.Clone(matched),
+		Original: Category{7: {Name: "Cat7", Path: "/cat7"}, 8: {Name: "Cat8", Path: "/cat8"}},
+		Tree:     []CategoryNode{{ID: 7}, {ID: 8}},
+		Deadline: time.Now().Add(DeadlineMargin / 2),
+	}
+	require.ErrorIs(t, pipeline.Run(testLogger(), st), ErrDeadlineReached)
+	require.Len(t, cp.Assignments, len(st.Dataset))
+
+	// The continuation reads the source rows in another order and, without consulting the remap
+	// or the current categories, writes exactly the remaining rows of the interrupted run.
+	slices.Reverse(matched)
+	resumed := NewMockCategoryStorer(t)
+	resumed.EXPECT().LockVersion(mock.Anything, "v1", false, time.Duration(0)).Return(unlockNop, nil)
+	resumed.EXPECT().VersionInfo("v1").Return(VersionInfo{}, ErrVersionNotFound)
+	resumed.EXPECT().Checkpoint("v1").Return(cp, nil)
+	resumed.EXPECT().MatchedCategory(MatchFilter{UpToID: 6}).Return(matched, nil)
+	resumed.EXPECT().CleanUpRows("v1", mock.AnythingOfType("[]int32")).Return(nil)
+	resumed.EXPECT().InsertDataset(st.Dataset[2:]).Return(nil)
+	resumed.EXPECT().SaveVersion("v1", int32(6), "").Return(nil)
+	resumed.EXPECT().DeleteCheckpoint("v1").Return(nil)
+
+	cfg.Resume = true
+	r, err := NewTransform(testLogger(), resumed, cfg).GenerateDataset(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, len(st.Dataset), r.Rows())
+}
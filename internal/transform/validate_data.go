@@ -0,0 +1,49 @@
+package transform
+
+import (
+	"errors"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+)
+
+var (
+	ErrInvalidData = errors.New("dataset contains invalid rows")
+)
+
+// DataReport counts the problems ValidateData found in the rows of a dataset version.
+// A row may be counted under several problems.
+type DataReport struct {
+	Rows            int `json:"rows"`
+	UnknownLabel    int `json:"unknown_label"`
+	MissingInput    int `json:"missing_input"`
+	MissingOutput   int `json:"missing_output"`
+	UnknownCategory int `json:"unknown_category"`
+}
+
+// Valid reports whether no problem was found.
+func (r DataReport) Valid() bool {
+	return r.UnknownLabel == 0 && r.MissingInput == 0 && r.MissingOutput == 0 && r.UnknownCategory == 0
+}
+
+// ValidateData checks generated dataset rows against the current taxonomy: every row must carry a known split
+// label, input text, a first input level, an output name and path, and a target category that still exists in original.
+func ValidateData(rows []model.CategoryDataset, original Category) DataReport {
+	report := DataReport{Rows: len(rows)}
+	for _, v := range rows {
+		switch v.Label {
+		case LabelTrain, LabelValidate, LabelTest:
+		default:
+			report.UnknownLabel++
+		}
+		if v.InputText == "" || v.L1In == "" {
+			report.MissingInput++
+		}
+		if v.NameOut == "" || v.FullPathOut == "" {
+			report.MissingOutput++
+		}
+		if _, ok := original[v.CategoryIDOut]; !ok {
+			report.UnknownCategory++
+		}
+	}
+	return report
+}
@@ -0,0 +1,35 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/opplieam/bb-transform/.jetgen/postgres/public/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateData(t *testing.T) {
+	original := Category{7: {Name: "Phones", Path: "Electronics > Phones"}}
+	valid := model.CategoryDataset{
+		L1In: "Shop", InputText: "Shop", NameOut: "Phones", FullPathOut: "Electronics > Phones", CategoryIDOut: 7, Label: LabelTrain,
+	}
+
+	report := ValidateData([]model.CategoryDataset{valid}, original)
+	assert.True(t, report.Valid())
+	assert.Equal(t, DataReport{Rows: 1}, report)
+
+	broken := valid
+	broken.Label = "holdout"
+	broken.CategoryIDOut = 9
+	broken.NameOut = ""
+	report = ValidateData([]model.CategoryDataset{valid, broken}, original)
+	assert.False(t, report.Valid())
+	assert.Equal(t, DataReport{Rows: 2, UnknownLabel: 1, MissingOutput: 1, UnknownCategory: 1}, report)
+
+	noText := valid
+	noText.InputText = ""
+	noLevel := valid
+	noLevel.L1In = ""
+	report = ValidateData([]model.CategoryDataset{valid, noText, noLevel}, original)
+	assert.False(t, report.Valid())
+	assert.Equal(t, DataReport{Rows: 3, MissingInput: 2}, report)
+}
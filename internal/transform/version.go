@@ -0,0 +1,46 @@
+package transform
+
+import (
+	"errors"
+	"time"
+)
+
+// Version statuses. Active versions are in use, retired versions are kept but should no longer be trained on,
+// and archived versions have been exported to a file and their rows removed from the dataset table.
+// Building versions are being generated by a fan-out generation whose partitions have not all completed,
+// or were reserved by ResolveVersion for a run that has not completed yet.
+const (
+	VersionStatusActive   = "active"
+	VersionStatusRetired  = "retired"
+	VersionStatusArchived = "archived"
+	VersionStatusBuilding = "building"
+)
+
+var (
+	ErrVersionNotFound  = errors.New("dataset version not found")
+	ErrVersionImmutable = errors.New("dataset version is immutable, set force to overwrite it")
+	ErrVersionLocked    = errors.New("dataset version is locked by another run")
+	ErrVersionBuilding  = errors.New("dataset version is being built by another run, delete it to start over")
+)
+
+// VersionInfo is the bookkeeping kept for every generated dataset version.
+// LastMatchID is the highest match_category ID processed for the version, which lets
+// incremental runs only pick up matched categories added since.
+// Immutable versions (e.g. ones a model was trained on) are never rewritten by GenerateDataset
+// unless Config.Force is set, and are skipped by retention policies.
+type VersionInfo struct {
+	Version     string    `json:"version"`
+	LastMatchID int32     `json:"last_match_id"`
+	Status      string    `json:"status"`
+	Immutable   bool      `json:"immutable"`
+	GeneratedBy *string   `json:"generated_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// VersionSummary is a VersionInfo together with the number of dataset rows per split label.
+type VersionSummary struct {
+	VersionInfo
+	Rows   int64            `json:"rows"`
+	Labels map[string]int64 `json:"labels"`
+}
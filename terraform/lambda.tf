@@ -14,6 +14,9 @@ module "lambda_function" {
   # Env
   environment_variables = {
     BUYBETTER_DEV_SUPABASE_DSN: var.BUYBETTER_DEV_SUPABASE_DSN
+    RESULT_QUEUE_URL: module.transform_result_queue.queue_url
+    RESULT_WEBHOOK_URL: var.result_webhook_url
+    TRANSFORM_QUEUE_URL: module.transform_queue.queue_url
   }
 
   create_package         = false
@@ -43,10 +46,16 @@ module "lambda_function" {
       actions = [
         "sqs:ReceiveMessage",
         "sqs:DeleteMessage",
+        "sqs:SendMessage",
         "sqs:GetQueueAttributes"
       ]
       resources = [module.transform_queue.queue_arn]
     }
+    sqs_result = {
+      effect    = "Allow"
+      actions   = ["sqs:SendMessage"]
+      resources = [module.transform_result_queue.queue_arn]
+    }
   }
 
   tags = {
@@ -27,6 +27,18 @@ module "transform_queue" {
   }
 }
 
+module "transform_result_queue" {
+  source  = "terraform-aws-modules/sqs/aws"
+  version = "4.2.1"
+
+  name = var.sqs_result_queue_name
+
+  tags = {
+    Environment = "prod"
+    Project     = "transform-category"
+  }
+}
+
 output "sqs_queue_url" {
   description = "The URL of the SQS Queue"
   value       = module.transform_queue.queue_url
@@ -35,4 +47,9 @@ output "sqs_queue_url" {
 output "dlq_queue_url" {
   description = "The URL of the Dead Letter Queue"
   value       = module.transform_queue_dlq.queue_url
-}
\ No newline at end of file
+}
+
+output "result_queue_url" {
+  description = "The URL of the job result queue"
+  value       = module.transform_result_queue.queue_url
+}
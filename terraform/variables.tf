@@ -22,4 +22,16 @@ variable "sqs_queue_name" {
 variable "sqs_dlq_name" {
   type = string
   description = "SQS dead letter queue name"
-}
\ No newline at end of file
+}
+
+variable "sqs_result_queue_name" {
+  type = string
+  description = "SQS queue name job results are published to"
+  default = "bb-transform-results"
+}
+
+variable "result_webhook_url" {
+  type = string
+  description = "Optional HTTP endpoint job results are posted to"
+  default = ""
+}